@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Felamande/githubMcp/model"
+	"github.com/google/go-github/v74/github"
+)
+
+// maxJobPages bounds the pages of jobs fetched for a workflow run.
+const maxJobPages = 10
+
+func (c *GithubClient) ListWorkflows(ctx context.Context, opt model.ListWorkflowsOption) (*model.ListWorkflowsResult, error) {
+	if opt.ResultPerpage == 0 {
+		opt.ResultPerpage = c.pageSize
+	}
+	if opt.Page == 0 {
+		opt.Page = 1
+	}
+
+	workflows, resp, err := c.c.Actions.ListWorkflows(ctx, opt.Owner, opt.Repository, &github.ListOptions{
+		PerPage: opt.ResultPerpage,
+		Page:    opt.Page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ListWorkflowsResult{
+		TotalCount: workflows.GetTotalCount(),
+		NextPage:   resp.NextPage,
+		LastPage:   resp.LastPage,
+		Workflows:  make([]model.WorkflowInfo, 0),
+	}
+	for _, workflow := range workflows.Workflows {
+		result.Workflows = append(result.Workflows, model.WorkflowInfo{
+			ID:        workflow.GetID(),
+			Name:      workflow.GetName(),
+			Path:      workflow.GetPath(),
+			State:     workflow.GetState(),
+			HTMLURL:   workflow.GetHTMLURL(),
+			CreatedAt: formatTimestamp(workflow.CreatedAt),
+			UpdatedAt: formatTimestamp(workflow.UpdatedAt),
+		})
+	}
+
+	return result, nil
+}
+
+func (c *GithubClient) ListWorkflowRuns(ctx context.Context, opt model.ListWorkflowRunsOption) (*model.ListWorkflowRunsResult, error) {
+	if opt.ResultPerpage == 0 {
+		opt.ResultPerpage = c.pageSize
+	}
+	if opt.Page == 0 {
+		opt.Page = 1
+	}
+
+	opts := &github.ListWorkflowRunsOptions{
+		Actor:   opt.Actor,
+		Branch:  opt.Branch,
+		Event:   opt.Event,
+		Status:  opt.Status,
+		HeadSHA: opt.HeadSHA,
+		ListOptions: github.ListOptions{
+			PerPage: opt.ResultPerpage,
+			Page:    opt.Page,
+		},
+	}
+
+	var runs *github.WorkflowRuns
+	var resp *github.Response
+	var err error
+	if opt.Workflow == "" {
+		runs, resp, err = c.c.Actions.ListRepositoryWorkflowRuns(ctx, opt.Owner, opt.Repository, opts)
+	} else if workflowID, parseErr := strconv.ParseInt(opt.Workflow, 10, 64); parseErr == nil {
+		runs, resp, err = c.c.Actions.ListWorkflowRunsByID(ctx, opt.Owner, opt.Repository, workflowID, opts)
+	} else {
+		runs, resp, err = c.c.Actions.ListWorkflowRunsByFileName(ctx, opt.Owner, opt.Repository, opt.Workflow, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ListWorkflowRunsResult{
+		TotalCount: runs.GetTotalCount(),
+		NextPage:   resp.NextPage,
+		LastPage:   resp.LastPage,
+		Runs:       make([]model.WorkflowRunInfo, 0),
+	}
+	for _, run := range runs.WorkflowRuns {
+		result.Runs = append(result.Runs, workflowRunInfo(run))
+	}
+
+	return result, nil
+}
+
+func workflowRunInfo(run *github.WorkflowRun) model.WorkflowRunInfo {
+	return model.WorkflowRunInfo{
+		ID:           run.GetID(),
+		WorkflowID:   run.GetWorkflowID(),
+		Name:         run.GetName(),
+		DisplayTitle: run.GetDisplayTitle(),
+		RunNumber:    run.GetRunNumber(),
+		RunAttempt:   run.GetRunAttempt(),
+		Event:        run.GetEvent(),
+		Status:       run.GetStatus(),
+		Conclusion:   run.GetConclusion(),
+		HeadBranch:   run.GetHeadBranch(),
+		HeadSHA:      run.GetHeadSHA(),
+		Actor:        run.GetActor().GetLogin(),
+		HTMLURL:      run.GetHTMLURL(),
+		CreatedAt:    formatTimestamp(run.CreatedAt),
+		UpdatedAt:    formatTimestamp(run.UpdatedAt),
+		RunStartedAt: formatTimestamp(run.RunStartedAt),
+	}
+}
+
+// GetWorkflowRun returns a workflow run with its jobs and their steps.
+func (c *GithubClient) GetWorkflowRun(ctx context.Context, opt model.GetWorkflowRunOption) (*model.WorkflowRunResult, error) {
+	run, _, err := c.c.Actions.GetWorkflowRunByID(ctx, opt.Owner, opt.Repository, opt.RunID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.WorkflowRunResult{
+		WorkflowRunInfo: workflowRunInfo(run),
+		Jobs:            make([]model.WorkflowJobInfo, 0),
+	}
+
+	filter := "latest"
+	if opt.AllAttempts {
+		filter = "all"
+	}
+	opts := &github.ListWorkflowJobsOptions{Filter: filter, ListOptions: github.ListOptions{PerPage: 100}}
+	for page := 0; page < maxJobPages; page++ {
+		jobs, resp, err := c.c.Actions.ListWorkflowJobs(ctx, opt.Owner, opt.Repository, opt.RunID, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.TotalJobs = jobs.GetTotalCount()
+		for _, job := range jobs.Jobs {
+			jobInfo := model.WorkflowJobInfo{
+				ID:          job.GetID(),
+				Name:        job.GetName(),
+				Status:      job.GetStatus(),
+				Conclusion:  job.GetConclusion(),
+				RunAttempt:  job.GetRunAttempt(),
+				RunnerName:  job.GetRunnerName(),
+				Labels:      job.Labels,
+				StartedAt:   formatTimestamp(job.StartedAt),
+				CompletedAt: formatTimestamp(job.CompletedAt),
+				HTMLURL:     job.GetHTMLURL(),
+				Steps:       make([]model.WorkflowStepInfo, 0),
+			}
+			for _, step := range job.Steps {
+				jobInfo.Steps = append(jobInfo.Steps, model.WorkflowStepInfo{
+					Number:      step.GetNumber(),
+					Name:        step.GetName(),
+					Status:      step.GetStatus(),
+					Conclusion:  step.GetConclusion(),
+					StartedAt:   formatTimestamp(step.StartedAt),
+					CompletedAt: formatTimestamp(step.CompletedAt),
+				})
+			}
+			result.Jobs = append(result.Jobs, jobInfo)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		result.JobLimitReached = page == maxJobPages-1
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
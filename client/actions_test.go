@@ -0,0 +1,280 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Felamande/githubMcp/model"
+	"github.com/google/go-github/v74/github"
+)
+
+// TestListWorkflowRunsFilters tests that run filters reach GitHub and workflows are addressed by ID or file name
+func TestListWorkflowRunsFilters(t *testing.T) {
+	var paths []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		query := r.URL.Query()
+		if query.Get("branch") != "main" || query.Get("event") != "push" || query.Get("status") != "failure" || query.Get("actor") != "alice" || query.Get("head_sha") != "abc" {
+			t.Errorf("Expected the filters to be sent, got %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total_count": 1,
+			"workflow_runs": []map[string]interface{}{{
+				"id": 7, "workflow_id": 3, "name": "CI", "run_number": 12, "event": "push",
+				"status": "completed", "conclusion": "failure", "head_branch": "main", "head_sha": "abc",
+				"actor": map[string]interface{}{"login": "alice"}, "created_at": "2024-05-01T10:00:00Z",
+			}},
+		})
+	})
+
+	for _, workflow := range []string{"", "3", "ci.yml"} {
+		runs, err := client.ListWorkflowRuns(context.Background(), model.ListWorkflowRunsOption{
+			Owner: "o", Repository: "r", Workflow: workflow, Branch: "main", Event: "push", Status: "failure", Actor: "alice", HeadSHA: "abc",
+		})
+		if err != nil {
+			t.Fatalf("ListWorkflowRuns failed: %v", err)
+		}
+		if runs.TotalCount != 1 || runs.Runs[0].ID != 7 || runs.Runs[0].Actor != "alice" || runs.Runs[0].CreatedAt != "2024-05-01T10:00:00Z" {
+			t.Errorf("Unexpected runs: %+v", runs)
+		}
+	}
+
+	expected := []string{"/api/v3/repos/o/r/actions/runs", "/api/v3/repos/o/r/actions/workflows/3/runs", "/api/v3/repos/o/r/actions/workflows/ci.yml/runs"}
+	for i, path := range expected {
+		if paths[i] != path {
+			t.Errorf("Expected request to %s, got %s", path, paths[i])
+		}
+	}
+}
+
+// TestGetWorkflowRun tests that a run is returned with the jobs and steps of its latest attempt
+func TestGetWorkflowRun(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/actions/runs/7":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 7, "name": "CI", "status": "completed", "conclusion": "failure", "run_attempt": 2})
+		case "/api/v3/repos/o/r/actions/runs/7/jobs":
+			if r.URL.Query().Get("filter") != "latest" {
+				t.Errorf("Expected the latest attempt, got filter %q", r.URL.Query().Get("filter"))
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"total_count": 1,
+				"jobs": []map[string]interface{}{{
+					"id": 70, "name": "test", "status": "completed", "conclusion": "failure", "run_attempt": 2,
+					"labels": []string{"ubuntu-latest"},
+					"steps": []map[string]interface{}{
+						{"number": 1, "name": "Checkout", "status": "completed", "conclusion": "success"},
+						{"number": 2, "name": "Run tests", "status": "completed", "conclusion": "failure"},
+					},
+				}},
+			})
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	})
+
+	run, err := client.GetWorkflowRun(context.Background(), model.GetWorkflowRunOption{Owner: "o", Repository: "r", RunID: 7})
+	if err != nil {
+		t.Fatalf("GetWorkflowRun failed: %v", err)
+	}
+	if run.ID != 7 || run.RunAttempt != 2 || len(run.Jobs) != 1 || run.TotalJobs != 1 || run.JobLimitReached {
+		t.Fatalf("Unexpected run: %+v", run)
+	}
+	job := run.Jobs[0]
+	if job.Conclusion != "failure" || len(job.Labels) != 1 || len(job.Steps) != 2 || job.Steps[1].Name != "Run tests" || job.Steps[1].Conclusion != "failure" {
+		t.Errorf("Unexpected job: %+v", job)
+	}
+}
+
+// TestGetWorkflowRunJobLimit tests that jobs cut off at the page limit are flagged
+func TestGetWorkflowRunJobLimit(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/actions/runs/7":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 7, "name": "CI"})
+		case "/api/v3/repos/o/r/actions/runs/7/jobs":
+			writeEndlessPage(w, r, map[string]interface{}{"total_count": 5000, "jobs": []map[string]interface{}{{"id": 70, "name": "test"}}})
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	})
+
+	run, err := client.GetWorkflowRun(context.Background(), model.GetWorkflowRunOption{Owner: "o", Repository: "r", RunID: 7})
+	if err != nil {
+		t.Fatalf("GetWorkflowRun failed: %v", err)
+	}
+	if !run.JobLimitReached || run.TotalJobs != 5000 || len(run.Jobs) != maxJobPages {
+		t.Errorf("Expected %d of 5000 jobs with the limit flagged, got %d of %d (%v)", maxJobPages, len(run.Jobs), run.TotalJobs, run.JobLimitReached)
+	}
+}
+
+// TestGetJobLogs tests line windows and the failures only mode of job logs
+func TestGetJobLogs(t *testing.T) {
+	logLines := []string{
+		"\ufeff2024-05-01T10:00:00.1000000Z ##[group]Run actions/checkout@v4",
+		"2024-05-01T10:00:01.2000000Z checked out",
+		"2024-05-01T10:00:02.3000000Z ##[endgroup]",
+		"2024-05-01T10:00:03.0000000Z go test ./...",
+		"2024-05-01T10:00:04.0000000Z ok  pkg/a",
+		"2024-05-01T10:00:05.0000000Z --- FAIL: TestB",
+		"2024-05-01T10:00:05.5000000Z FAIL pkg/b",
+		"2024-05-01T10:00:06.0000000Z ##[error]Process completed with exit code 1.",
+		"2024-05-01T10:00:07.0000000Z Post job cleanup.",
+	}
+	downloads := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/actions/jobs/70":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": 70, "name": "test", "status": "completed", "conclusion": "failure",
+				"steps": []map[string]interface{}{
+					{"number": 1, "name": "Checkout", "conclusion": "success", "started_at": "2024-05-01T10:00:00Z", "completed_at": "2024-05-01T10:00:02Z"},
+					{"number": 2, "name": "Run tests", "conclusion": "failure", "started_at": "2024-05-01T10:00:03Z", "completed_at": "2024-05-01T10:00:06Z"},
+					{"number": 3, "name": "Cleanup", "conclusion": "success", "started_at": "2024-05-01T10:00:07Z", "completed_at": "2024-05-01T10:00:07Z"},
+				},
+			})
+		case "/api/v3/repos/o/r/actions/jobs/70/logs":
+			http.Redirect(w, r, server.URL+"/logs/70.txt", http.StatusFound)
+		case "/logs/70.txt":
+			downloads++
+			w.Write([]byte(strings.Join(logLines, "\n") + "\n"))
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("", WithEnterpriseURLs(server.URL, ""), WithDownloadDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	logs, err := client.GetJobLogs(context.Background(), model.GetJobLogsOption{Owner: "o", Repository: "r", JobID: 70, StartLine: 4, EndLine: 5})
+	if err != nil {
+		t.Fatalf("GetJobLogs failed: %v", err)
+	}
+	if logs.TotalLines != 9 || logs.Content != "go test ./...\nok  pkg/a" || logs.Conclusion != "failure" {
+		t.Errorf("Unexpected log window: %+v", logs)
+	}
+
+	logs, err = client.GetJobLogs(context.Background(), model.GetJobLogsOption{Owner: "o", Repository: "r", JobID: 70, FailuresOnly: true, TailLines: github.Ptr(2), ContextLines: github.Ptr(1), Timestamps: true})
+	if err != nil {
+		t.Fatalf("GetJobLogs failed: %v", err)
+	}
+	if len(logs.Sections) != 1 {
+		t.Fatalf("Expected the error and the failed step tail to merge into one section, got %+v", logs.Sections)
+	}
+	section := logs.Sections[0]
+	if section.StartLine != 7 || section.EndLine != 8 || section.Step != "Run tests" || section.Reason != "error annotation, end of failed step" {
+		t.Errorf("Unexpected section: %+v", section)
+	}
+	if !strings.HasPrefix(section.Content, "2024-05-01T10:00:05.5000000Z FAIL pkg/b") {
+		t.Errorf("Expected timestamps to be kept, got %q", section.Content)
+	}
+
+	logs, err = client.GetJobLogs(context.Background(), model.GetJobLogsOption{Owner: "o", Repository: "r", JobID: 70, FailuresOnly: true, TailLines: github.Ptr(0), ContextLines: github.Ptr(0)})
+	if err != nil {
+		t.Fatalf("GetJobLogs failed: %v", err)
+	}
+	if len(logs.Sections) != 1 || logs.Sections[0].StartLine != 8 || logs.Sections[0].EndLine != 8 || logs.Sections[0].Reason != "error annotation" {
+		t.Errorf("Expected zero tail and context lines to leave only the annotation, got %+v", logs.Sections)
+	}
+
+	if downloads != 1 {
+		t.Errorf("Expected the log of a completed job to be downloaded once, got %d", downloads)
+	}
+}
+
+// TestListRunArtifactsByName tests that the name filter of run artifacts is applied by GitHub before paging
+func TestListRunArtifactsByName(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/o/r/actions/runs/7/artifacts" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("name") != "test-results" || r.URL.Query().Get("per_page") != "1" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total_count": 1,
+			"artifacts":   []map[string]interface{}{{"id": 5, "name": "test-results", "workflow_run": map[string]interface{}{"id": 7}}},
+		})
+	})
+
+	result, err := client.ListArtifacts(context.Background(), model.ListArtifactsOption{Owner: "o", Repository: "r", RunID: 7, Name: "test-results", ResultPerpage: 1})
+	if err != nil {
+		t.Fatalf("ListArtifacts failed: %v", err)
+	}
+	if result.TotalCount != 1 || len(result.Artifacts) != 1 || result.Artifacts[0].ID != 5 || result.Artifacts[0].RunID != 7 {
+		t.Errorf("Unexpected artifacts: %+v", result)
+	}
+}
+
+// TestReadArtifact tests that an artifact zip is downloaded once, listed and read by entry
+func TestReadArtifact(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"report/junit.xml": "<testsuite>\n<failure/>\n</testsuite>", "coverage.out": "mode: set"} {
+		f, _ := zw.Create(name)
+		f.Write([]byte(content))
+	}
+	zw.Close()
+
+	downloads := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/actions/artifacts/5":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 5, "name": "test-results", "size_in_bytes": buf.Len(), "workflow_run": map[string]interface{}{"id": 7}})
+		case "/api/v3/repos/o/r/actions/artifacts/5/zip":
+			http.Redirect(w, r, server.URL+"/blobs/5.zip", http.StatusFound)
+		case "/blobs/5.zip":
+			downloads++
+			w.Write(buf.Bytes())
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("", WithEnterpriseURLs(server.URL, ""), WithDownloadDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	listing, err := client.ReadArtifact(context.Background(), model.ReadArtifactOption{Owner: "o", Repository: "r", ArtifactID: 5})
+	if err != nil {
+		t.Fatalf("ReadArtifact failed: %v", err)
+	}
+	if listing.Artifact.Name != "test-results" || listing.Artifact.RunID != 7 || len(listing.Entries) != 2 {
+		t.Errorf("Unexpected listing: %+v", listing)
+	}
+
+	entry, err := client.ReadArtifact(context.Background(), model.ReadArtifactOption{Owner: "o", Repository: "r", ArtifactID: 5, Path: "report/junit.xml", StartLine: 2, EndLine: 2})
+	if err != nil {
+		t.Fatalf("ReadArtifact failed: %v", err)
+	}
+	if entry.File == nil || entry.File.Content != "<failure/>" || entry.File.TotalLines != 3 {
+		t.Errorf("Unexpected entry: %+v", entry.File)
+	}
+
+	if _, err := client.ReadArtifact(context.Background(), model.ReadArtifactOption{Owner: "o", Repository: "r", ArtifactID: 5, Path: "missing.txt"}); err == nil {
+		t.Errorf("Expected an error for a missing entry")
+	}
+	if downloads != 1 {
+		t.Errorf("Expected the artifact to be downloaded once, got %d", downloads)
+	}
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+)
+
+const (
+	// appJWTLifetime stays below the 10 minutes GitHub accepts for an App JWT.
+	appJWTLifetime = 9 * time.Minute
+	// appTokenRefreshBefore renews installation tokens this long before they expire.
+	appTokenRefreshBefore = 5 * time.Minute
+)
+
+// appTokenSource mints installation access tokens of a GitHub App and keeps
+// the current one until it is about to expire.
+type appTokenSource struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	apps           *github.AppsService
+	now            func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func parseAppPrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %v", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// jwt signs the RS256 token authenticating as the App itself.
+func (s *appTokenSource) jwt() (string, error) {
+	now := s.now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		// backdated to allow for clock drift between us and GitHub
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(s.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Token returns a valid installation token, minting a new one when the
+// current token expires within appTokenRefreshBefore.
+func (s *appTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.now().Add(appTokenRefreshBefore).Before(s.expiresAt) {
+		return s.token, nil
+	}
+
+	token, _, err := s.apps.CreateInstallationToken(ctx, s.installationID, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create installation token: %w", err)
+	}
+	s.token = token.GetToken()
+	s.expiresAt = token.GetExpiresAt().Time
+	return s.token, nil
+}
+
+// appJWTTransport authenticates requests as the App, which is only needed to
+// mint installation tokens.
+type appJWTTransport struct {
+	base   http.RoundTripper
+	source *appTokenSource
+}
+
+func (t *appJWTTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jwt, err := t.source.jwt()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	return t.base.RoundTrip(req)
+}
+
+// appInstallationTransport authenticates requests as the App installation.
+type appInstallationTransport struct {
+	base   http.RoundTripper
+	source *appTokenSource
+}
+
+func (t *appInstallationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "token "+token)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+func verifyAppJWT(t *testing.T, key *rsa.PublicKey, authorization string) map[string]interface{} {
+	t.Helper()
+	jwt, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		t.Fatalf("Expected a bearer JWT, got %q", authorization)
+	}
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 JWT parts, got %d", len(parts))
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("Invalid JWT signature encoding: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("Invalid JWT signature: %v", err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("Invalid JWT payload encoding: %v", err)
+	}
+	claims := make(map[string]interface{})
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("Invalid JWT payload: %v", err)
+	}
+	return claims
+}
+
+// TestAppAuthInstallationToken tests that installation tokens are minted with a signed JWT and renewed before expiry
+func TestAppAuthInstallationToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	minted := 0
+	expiresIn := 2 * time.Minute
+	var usedTokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/app/installations/42/access_tokens":
+			if r.Method != http.MethodPost {
+				t.Errorf("Expected POST to mint a token, got %s", r.Method)
+			}
+			claims := verifyAppJWT(t, &key.PublicKey, r.Header.Get("Authorization"))
+			if claims["iss"] != "7" {
+				t.Errorf("Expected issuer 7, got %v", claims["iss"])
+			}
+			minted++
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"token":      fmt.Sprintf("installation-token-%d", minted),
+				"expires_at": time.Now().Add(expiresIn).UTC().Format(time.RFC3339),
+			})
+		case "/api/v3/repos/o/r/branches/main":
+			usedTokens = append(usedTokens, r.Header.Get("Authorization"))
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": "main"})
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("", WithEnterpriseURLs(server.URL, ""), WithAppAuth(7, 42, keyPEM))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	opt := model.GetBranchByNameOption{Owner: "o", Repository: "r", BranchName: "main"}
+
+	// the first token expires within the refresh window and is renewed on the next call
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetBranchByName(context.Background(), opt); err != nil {
+			t.Fatalf("GetBranchByName failed: %v", err)
+		}
+		expiresIn = time.Hour
+	}
+	// the second token is valid for an hour and is reused
+	if _, err := client.GetBranchByName(context.Background(), opt); err != nil {
+		t.Fatalf("GetBranchByName failed: %v", err)
+	}
+
+	if minted != 2 {
+		t.Errorf("Expected 2 minted tokens, got %d", minted)
+	}
+	expected := []string{"token installation-token-1", "token installation-token-2", "token installation-token-2"}
+	if strings.Join(usedTokens, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected tokens %v, got %v", expected, usedTokens)
+	}
+}
+
+func TestAppAuthOptionValidation(t *testing.T) {
+	if _, err := NewClient("", WithAppAuth(1, 2, []byte("not a key"))); err == nil {
+		t.Errorf("Expected an error for an invalid private key")
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey failed: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if _, err := NewClient("", WithAppAuth(1, 2, keyPEM)); err != nil {
+		t.Errorf("Expected a PKCS8 key to be accepted, got %v", err)
+	}
+	if _, err := NewClient("token", WithAppAuth(1, 2, keyPEM)); err == nil {
+		t.Errorf("Expected an error when combining a token with app auth")
+	}
+}
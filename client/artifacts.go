@@ -0,0 +1,160 @@
+package client
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+	"github.com/google/go-github/v74/github"
+)
+
+// maxArtifactEntrySize bounds the size of an artifact entry read into memory.
+const maxArtifactEntrySize = 64 << 20
+
+func artifactInfo(artifact *github.Artifact) model.ArtifactInfo {
+	return model.ArtifactInfo{
+		ID:          artifact.GetID(),
+		Name:        artifact.GetName(),
+		SizeInBytes: artifact.GetSizeInBytes(),
+		Expired:     artifact.GetExpired(),
+		RunID:       artifact.GetWorkflowRun().GetID(),
+		HeadBranch:  artifact.GetWorkflowRun().GetHeadBranch(),
+		HeadSHA:     artifact.GetWorkflowRun().GetHeadSHA(),
+		CreatedAt:   formatTimestamp(artifact.CreatedAt),
+		ExpiresAt:   formatTimestamp(artifact.ExpiresAt),
+	}
+}
+
+func (c *GithubClient) ListArtifacts(ctx context.Context, opt model.ListArtifactsOption) (*model.ListArtifactsResult, error) {
+	if opt.ResultPerpage == 0 {
+		opt.ResultPerpage = c.pageSize
+	}
+	if opt.Page == 0 {
+		opt.Page = 1
+	}
+	listOpts := github.ListOptions{
+		PerPage: opt.ResultPerpage,
+		Page:    opt.Page,
+	}
+
+	var artifacts *github.ArtifactList
+	var resp *github.Response
+	var err error
+	if opt.RunID != 0 {
+		artifacts, resp, err = c.listRunArtifacts(ctx, opt)
+	} else {
+		opts := &github.ListArtifactsOptions{ListOptions: listOpts}
+		if opt.Name != "" {
+			opts.Name = &opt.Name
+		}
+		artifacts, resp, err = c.c.Actions.ListArtifacts(ctx, opt.Owner, opt.Repository, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ListArtifactsResult{
+		TotalCount: int(artifacts.GetTotalCount()),
+		NextPage:   resp.NextPage,
+		LastPage:   resp.LastPage,
+		Artifacts:  make([]model.ArtifactInfo, 0),
+	}
+	for _, artifact := range artifacts.Artifacts {
+		result.Artifacts = append(result.Artifacts, artifactInfo(artifact))
+	}
+
+	return result, nil
+}
+
+// listRunArtifacts lists the artifacts of a workflow run. go-github does not
+// pass the name filter of this endpoint, so the request is built by hand.
+func (c *GithubClient) listRunArtifacts(ctx context.Context, opt model.ListArtifactsOption) (*github.ArtifactList, *github.Response, error) {
+	query := url.Values{}
+	if opt.Name != "" {
+		query.Set("name", opt.Name)
+	}
+	query.Set("per_page", strconv.Itoa(opt.ResultPerpage))
+	query.Set("page", strconv.Itoa(opt.Page))
+
+	req, err := c.c.NewRequest("GET", fmt.Sprintf("repos/%s/%s/actions/runs/%d/artifacts?%s", opt.Owner, opt.Repository, opt.RunID, query.Encode()), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	artifacts := &github.ArtifactList{}
+	resp, err := c.c.Do(ctx, req, artifacts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return artifacts, resp, nil
+}
+
+// ReadArtifact lists the entries of an artifact or reads one of them. The
+// artifact zip is downloaded once and kept in the download directory.
+func (c *GithubClient) ReadArtifact(ctx context.Context, opt model.ReadArtifactOption) (*model.ReadArtifactResult, error) {
+	artifact, _, err := c.c.Actions.GetArtifact(ctx, opt.Owner, opt.Repository, opt.ArtifactID)
+	if err != nil {
+		return nil, err
+	}
+	if artifact.GetExpired() {
+		return nil, fmt.Errorf("artifact %d has expired", opt.ArtifactID)
+	}
+
+	dest := c.downloadPath("artifacts", opt.Owner+"/"+opt.Repository+"#"+strconv.FormatInt(opt.ArtifactID, 10), ".zip")
+	if !c.downloaded(dest) {
+		link, _, err := c.c.Actions.DownloadArtifact(ctx, opt.Owner, opt.Repository, opt.ArtifactID, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get artifact link: %w", err)
+		}
+		if err := c.downloadOnce(ctx, link.String(), dest); err != nil {
+			return nil, err
+		}
+	}
+
+	archive, err := zip.OpenReader(dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact zip: %v", err)
+	}
+	defer archive.Close()
+
+	result := &model.ReadArtifactResult{Artifact: artifactInfo(artifact)}
+	if opt.Path == "" {
+		result.Entries = make([]model.ArtifactEntry, 0)
+		for _, entry := range archive.File {
+			if entry.FileInfo().IsDir() {
+				continue
+			}
+			result.Entries = append(result.Entries, model.ArtifactEntry{
+				Path:     entry.Name,
+				Size:     entry.UncompressedSize64,
+				Modified: entry.Modified.Format(time.RFC3339),
+			})
+		}
+		return result, nil
+	}
+
+	for _, entry := range archive.File {
+		if entry.Name != opt.Path {
+			continue
+		}
+		if entry.UncompressedSize64 > maxArtifactEntrySize {
+			return nil, fmt.Errorf("artifact entry %s exceeds %d bytes", opt.Path, maxArtifactEntrySize)
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, maxArtifactEntrySize))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifact entry %s: %v", opt.Path, err)
+		}
+		result.File = fileResult(entry.Name, data, model.ReadFileOption{StartLine: opt.StartLine, EndLine: opt.EndLine})
+		return result, nil
+	}
+	return nil, fmt.Errorf("artifact %d has no entry %s", opt.ArtifactID, opt.Path)
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+const blameQuery = `query($owner: String!, $name: String!, $ref: String!, $path: String!) {
+  repository(owner: $owner, name: $name) {
+    object(expression: $ref) {
+      ... on Commit {
+        oid
+        blame(path: $path) {
+          ranges {
+            startingLine
+            endingLine
+            age
+            commit {
+              oid
+              messageHeadline
+              url
+              author { name email date user { login } }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type blameData struct {
+	Repository *struct {
+		Object *struct {
+			OID   string `json:"oid"`
+			Blame *struct {
+				Ranges []struct {
+					StartingLine int `json:"startingLine"`
+					EndingLine   int `json:"endingLine"`
+					Age          int `json:"age"`
+					Commit       struct {
+						OID             string `json:"oid"`
+						MessageHeadline string `json:"messageHeadline"`
+						URL             string `json:"url"`
+						Author          struct {
+							Name  string    `json:"name"`
+							Email string    `json:"email"`
+							Date  time.Time `json:"date"`
+							User  *struct {
+								Login string `json:"login"`
+							} `json:"user"`
+						} `json:"author"`
+					} `json:"commit"`
+				} `json:"ranges"`
+			} `json:"blame"`
+		} `json:"object"`
+	} `json:"repository"`
+}
+
+// GetBlame returns the commit that last changed each line of a file, grouped
+// into hunks of consecutive lines or expanded per line.
+func (c *GithubClient) GetBlame(ctx context.Context, opt model.GetBlameOption) (*model.BlameResult, error) {
+	if opt.Ref == "" {
+		opt.Ref = "HEAD"
+	}
+	if opt.StartLine == 0 {
+		opt.StartLine = 1
+	}
+	if opt.StartLine < 1 || (opt.EndLine > 0 && opt.EndLine < opt.StartLine) {
+		return nil, fmt.Errorf("invalid line range: %d-%d", opt.StartLine, opt.EndLine)
+	}
+
+	data := &blameData{}
+	err := c.graphql(ctx, blameQuery, map[string]interface{}{
+		"owner": opt.Owner,
+		"name":  opt.Repository,
+		"ref":   opt.Ref,
+		"path":  opt.Path,
+	}, data)
+	if err != nil {
+		return nil, err
+	}
+	if data.Repository == nil {
+		return nil, fmt.Errorf("repository not found: %s/%s", opt.Owner, opt.Repository)
+	}
+	object := data.Repository.Object
+	if object == nil || object.Blame == nil {
+		return nil, fmt.Errorf("ref %s does not resolve to a commit", opt.Ref)
+	}
+
+	result := &model.BlameResult{
+		CommitSHA: object.OID,
+		Path:      opt.Path,
+		StartLine: opt.StartLine,
+		Hunks:     make([]model.BlameHunk, 0),
+	}
+	for _, r := range object.Blame.Ranges {
+		result.TotalLines = max(result.TotalLines, r.EndingLine)
+		start, end := max(r.StartingLine, opt.StartLine), r.EndingLine
+		if opt.EndLine > 0 {
+			end = min(end, opt.EndLine)
+		}
+		if start > end {
+			continue
+		}
+
+		hunk := model.BlameHunk{
+			StartLine:       start,
+			EndLine:         end,
+			CommitSHA:       r.Commit.OID,
+			Author:          r.Commit.Author.Name,
+			AuthorEmail:     r.Commit.Author.Email,
+			Date:            r.Commit.Author.Date.Format(time.RFC3339),
+			MessageHeadline: r.Commit.MessageHeadline,
+			HTMLURL:         r.Commit.URL,
+			Age:             r.Age,
+		}
+		if r.Commit.Author.User != nil {
+			hunk.AuthorLogin = r.Commit.Author.User.Login
+		}
+		if !opt.PerLine {
+			result.Hunks = append(result.Hunks, hunk)
+			continue
+		}
+		for line := start; line <= end; line++ {
+			hunk.StartLine, hunk.EndLine = line, line
+			result.Hunks = append(result.Hunks, hunk)
+		}
+	}
+	result.EndLine = result.TotalLines
+	if opt.EndLine > 0 {
+		result.EndLine = min(opt.EndLine, result.TotalLines)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+func blameRange(start, end int, sha, login string) map[string]interface{} {
+	author := map[string]interface{}{"name": "Author " + sha, "email": sha + "@example.com", "date": "2024-05-01T10:00:00Z", "user": nil}
+	if login != "" {
+		author["user"] = map[string]interface{}{"login": login}
+	}
+	return map[string]interface{}{
+		"startingLine": start,
+		"endingLine":   end,
+		"age":          3,
+		"commit":       map[string]interface{}{"oid": sha, "messageHeadline": "change " + sha, "url": "https://github.com/o/r/commit/" + sha, "author": author},
+	}
+}
+
+// TestGetBlame tests that blame ranges are clipped to the line range and expanded per line
+func TestGetBlame(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" || r.Method != http.MethodPost {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		var req graphqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Invalid GraphQL request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if req.Variables["path"] != "main.go" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data":   map[string]interface{}{"repository": map[string]interface{}{"object": nil}},
+				"errors": []map[string]interface{}{{"type": "NOT_FOUND", "message": "Could not resolve file"}},
+			})
+			return
+		}
+		if req.Variables["ref"] != "HEAD" {
+			t.Errorf("Expected the default ref HEAD, got %v", req.Variables["ref"])
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"repository": map[string]interface{}{"object": map[string]interface{}{
+				"oid": "head",
+				"blame": map[string]interface{}{"ranges": []interface{}{
+					blameRange(1, 3, "aaa", "alice"),
+					blameRange(4, 5, "bbb", ""),
+					blameRange(6, 9, "ccc", "carol"),
+				}},
+			}}},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("", WithEnterpriseURLs(server.URL, ""))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	blame, err := client.GetBlame(context.Background(), model.GetBlameOption{Owner: "o", Repository: "r", Path: "main.go", StartLine: 3, EndLine: 6})
+	if err != nil {
+		t.Fatalf("GetBlame failed: %v", err)
+	}
+	if blame.CommitSHA != "head" || blame.TotalLines != 9 || blame.EndLine != 6 || len(blame.Hunks) != 3 {
+		t.Fatalf("Unexpected blame: %+v", blame)
+	}
+	first := blame.Hunks[0]
+	if first.StartLine != 3 || first.EndLine != 3 || first.AuthorLogin != "alice" || first.MessageHeadline != "change aaa" || first.Date != "2024-05-01T10:00:00Z" {
+		t.Errorf("Unexpected first hunk: %+v", first)
+	}
+	if last := blame.Hunks[2]; last.StartLine != 6 || last.EndLine != 6 {
+		t.Errorf("Expected the last hunk to be clipped to line 6, got %+v", last)
+	}
+
+	blame, err = client.GetBlame(context.Background(), model.GetBlameOption{Owner: "o", Repository: "r", Path: "main.go", EndLine: 5, PerLine: true})
+	if err != nil {
+		t.Fatalf("GetBlame failed: %v", err)
+	}
+	if len(blame.Hunks) != 5 || blame.Hunks[4].StartLine != 5 || blame.Hunks[4].CommitSHA != "bbb" {
+		t.Errorf("Expected one entry per line, got %+v", blame.Hunks)
+	}
+
+	if _, err := client.GetBlame(context.Background(), model.GetBlameOption{Owner: "o", Repository: "r", Path: "missing.go"}); err == nil {
+		t.Errorf("Expected the GraphQL error to be returned")
+	}
+}
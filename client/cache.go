@@ -0,0 +1,268 @@
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+// maxCacheEntrySize bounds the size of a response body kept in the cache.
+const maxCacheEntrySize = 8 << 20
+
+// cacheEntry is a stored 200 response together with its validators.
+type cacheEntry struct {
+	Key          string      `json:"key"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag"`
+	LastModified string      `json:"last_modified"`
+	ValidatedAt  time.Time   `json:"validated_at"`
+}
+
+// cacheTransport revalidates cached GET responses with If-None-Match and
+// If-Modified-Since. GitHub answers unchanged resources with 304, which does
+// not count against the rate limit, and the stored body is served instead.
+// Entries are kept in a LRU in memory and optionally on disk, entries not
+// validated within the TTL are dropped.
+type cacheTransport struct {
+	base       http.RoundTripper
+	maxEntries int
+	ttl        time.Duration
+	dir        string
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+	stats   model.CacheStatsResult
+}
+
+func newCacheTransport(base http.RoundTripper, maxEntries int, ttl time.Duration, dir string) *cacheTransport {
+	return &cacheTransport{
+		base:       base,
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		dir:        dir,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// the token is left out of the key so that rotated tokens keep their entries
+	key := req.Header.Get("Accept") + " " + req.URL.String()
+	entry := t.get(key)
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		t.mu.Lock()
+		t.stats.Hits++
+		t.mu.Unlock()
+
+		validated := *entry
+		validated.ValidatedAt = time.Now()
+		t.put(&validated)
+		return cachedResponse(req, &validated, resp.Header), nil
+	}
+
+	t.mu.Lock()
+	t.stats.Misses++
+	t.mu.Unlock()
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+	if resp.ContentLength > maxCacheEntrySize {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCacheEntrySize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCacheEntrySize {
+		// too large to keep, the caller reads the rest from the original body
+		resp.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.put(&cacheEntry{
+		Key:          key,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		ETag:         etag,
+		LastModified: lastModified,
+		ValidatedAt:  time.Now(),
+	})
+	return resp, nil
+}
+
+// prefixedBody is a response body whose first bytes were already read.
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// cachedResponse rebuilds a 200 response from entry, taking the rate limit
+// headers from the 304 response so the client keeps tracking its quota.
+func cachedResponse(req *http.Request, entry *cacheEntry, notModified http.Header) *http.Response {
+	header := entry.Header.Clone()
+	for _, name := range []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-RateLimit-Used", "X-RateLimit-Resource"} {
+		if value := notModified.Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+	header.Set("X-From-Cache", "1")
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
+
+func (t *cacheTransport) expired(entry *cacheEntry) bool {
+	return t.ttl > 0 && time.Since(entry.ValidatedAt) > t.ttl
+}
+
+func (t *cacheTransport) get(key string) *cacheEntry {
+	t.mu.Lock()
+	if elem, ok := t.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if !t.expired(entry) {
+			t.lru.MoveToFront(elem)
+			t.mu.Unlock()
+			return entry
+		}
+		t.lru.Remove(elem)
+		delete(t.entries, key)
+	}
+	t.mu.Unlock()
+
+	if t.dir == "" {
+		return nil
+	}
+	entry, err := t.readDisk(key)
+	if err != nil || entry.Key != key {
+		return nil
+	}
+	if t.expired(entry) {
+		os.Remove(t.diskPath(key))
+		return nil
+	}
+	t.putMemory(entry)
+	return entry
+}
+
+func (t *cacheTransport) put(entry *cacheEntry) {
+	t.putMemory(entry)
+	if t.dir != "" {
+		// the disk store is best effort, a failed write only costs a full request later
+		t.writeDisk(entry)
+	}
+}
+
+func (t *cacheTransport) putMemory(entry *cacheEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if elem, ok := t.entries[entry.Key]; ok {
+		elem.Value = entry
+		t.lru.MoveToFront(elem)
+		return
+	}
+	t.entries[entry.Key] = t.lru.PushFront(entry)
+	t.stats.Stores++
+	for t.lru.Len() > t.maxEntries {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		delete(t.entries, oldest.Value.(*cacheEntry).Key)
+		t.stats.Evictions++
+	}
+}
+
+func (t *cacheTransport) diskPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(t.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (t *cacheTransport) readDisk(key string) (*cacheEntry, error) {
+	data, err := os.ReadFile(t.diskPath(key))
+	if err != nil {
+		return nil, err
+	}
+	entry := &cacheEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (t *cacheTransport) writeDisk(entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(t.diskPath(entry.Key), func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+func (t *cacheTransport) statistics() model.CacheStatsResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := t.stats
+	stats.Enabled = true
+	stats.Entries = t.lru.Len()
+	stats.MaxEntries = t.maxEntries
+	stats.TTL = t.ttl.String()
+	stats.Dir = t.dir
+	return stats
+}
+
+// GetCacheStats reports the hits and misses of the response cache.
+func (c *GithubClient) GetCacheStats(ctx context.Context, opt model.CacheStatsOption) (*model.CacheStatsResult, error) {
+	if c.cache == nil {
+		return &model.CacheStatsResult{}, nil
+	}
+	stats := c.cache.statistics()
+	return &stats, nil
+}
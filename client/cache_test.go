@@ -0,0 +1,162 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+func newCacheTestServer(t *testing.T, content *string, requests *[]*http.Request) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r)
+		etag := `"` + base64.StdEncoding.EncodeToString([]byte(*content)) + `"`
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(*content)),
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestCacheRevalidation tests that unchanged files are served from the cache after a 304
+func TestCacheRevalidation(t *testing.T) {
+	content := "first version"
+	var requests []*http.Request
+	server := newCacheTestServer(t, &content, &requests)
+
+	client, err := NewClient("", WithEnterpriseURLs(server.URL, ""), WithCache(10, time.Hour, ""))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	opt := model.ReadFileOption{Owner: "o", Repository: "r", Path: "README"}
+
+	for i := 0; i < 2; i++ {
+		file, err := client.ReadFile(context.Background(), opt)
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		if file.Content != "first version" {
+			t.Errorf("Expected cached content, got %q", file.Content)
+		}
+	}
+	if requests[1].Header.Get("If-None-Match") == "" {
+		t.Errorf("Expected the second request to be conditional")
+	}
+
+	content = "second version"
+	file, err := client.ReadFile(context.Background(), opt)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if file.Content != "second version" {
+		t.Errorf("Expected changed content, got %q", file.Content)
+	}
+
+	stats, err := client.GetCacheStats(context.Background(), model.CacheStatsOption{})
+	if err != nil {
+		t.Fatalf("GetCacheStats failed: %v", err)
+	}
+	if !stats.Enabled || stats.Hits != 1 || stats.Misses != 2 || stats.Entries != 1 {
+		t.Errorf("Unexpected cache stats: %+v", stats)
+	}
+}
+
+// TestCacheDiskStore tests that entries written to disk are revalidated by a new client
+func TestCacheDiskStore(t *testing.T) {
+	content := "on disk"
+	var requests []*http.Request
+	server := newCacheTestServer(t, &content, &requests)
+	dir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		client, err := NewClient("", WithEnterpriseURLs(server.URL, ""), WithCache(10, time.Hour, dir))
+		if err != nil {
+			t.Fatalf("NewClient failed: %v", err)
+		}
+		file, err := client.ReadFile(context.Background(), model.ReadFileOption{Owner: "o", Repository: "r", Path: "README"})
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		if file.Content != "on disk" {
+			t.Errorf("Expected content from disk, got %q", file.Content)
+		}
+	}
+	if len(requests) != 2 || requests[1].Header.Get("If-None-Match") == "" {
+		t.Errorf("Expected the second client to revalidate the entry from disk")
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	cache := newCacheTransport(http.DefaultTransport, 2, 0, "")
+	for _, key := range []string{"a", "b", "c"} {
+		cache.put(&cacheEntry{Key: key, ValidatedAt: time.Now()})
+	}
+	if cache.get("a") != nil {
+		t.Errorf("Expected the least recently used entry to be evicted")
+	}
+	if cache.get("b") == nil || cache.get("c") == nil {
+		t.Errorf("Expected the recent entries to be kept")
+	}
+
+	expiring := newCacheTransport(http.DefaultTransport, 2, time.Minute, "")
+	expiring.put(&cacheEntry{Key: "old", ValidatedAt: time.Now().Add(-time.Hour)})
+	if expiring.get("old") != nil {
+		t.Errorf("Expected an entry older than the ttl to be dropped")
+	}
+}
+
+// TestCacheOversizedChunkedBody tests that a chunked response over the entry size limit is passed through whole and not cached
+func TestCacheOversizedChunkedBody(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), maxCacheEntrySize/16+1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"large"`)
+		// flushing before writing the body forces a chunked response without Content-Length
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for i := 0; i < len(data); i += 1 << 20 {
+			w.Write(data[i:min(i+1<<20, len(data))])
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	cache := newCacheTransport(http.DefaultTransport, 10, time.Hour, "")
+	client := &http.Client{Transport: cache}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if resp.ContentLength != -1 {
+		t.Fatalf("Expected a chunked response, got Content-Length %d", resp.ContentLength)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(body, data) {
+		t.Errorf("Expected the whole body of %d bytes, got %d bytes", len(data), len(body))
+	}
+	if stats := cache.statistics(); stats.Entries != 0 {
+		t.Errorf("Expected the oversized response not to be cached, got %d entries", stats.Entries)
+	}
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+	"github.com/google/go-github/v74/github"
+)
+
+// Rollup states shared by statuses and check runs.
+const (
+	checkStateSuccess = "success"
+	checkStateFailure = "failure"
+	checkStatePending = "pending"
+	checkStateNone    = "none"
+)
+
+// maxCheckPages bounds the pages fetched of check runs, suites and statuses.
+const maxCheckPages = 10
+
+func formatTimestamp(ts *github.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.Format(time.RFC3339)
+}
+
+// checkRunState maps a check run to a rollup state. Neutral and skipped runs
+// do not block, stale runs were superseded and wait for a new run.
+func checkRunState(status, conclusion string) string {
+	if status != "completed" {
+		return checkStatePending
+	}
+	switch conclusion {
+	case "success", "neutral", "skipped":
+		return checkStateSuccess
+	case "stale":
+		return checkStatePending
+	default:
+		return checkStateFailure
+	}
+}
+
+// commitStatusState maps the state of a commit status to a rollup state.
+func commitStatusState(state string) string {
+	switch state {
+	case "success":
+		return checkStateSuccess
+	case "pending":
+		return checkStatePending
+	default:
+		return checkStateFailure
+	}
+}
+
+// rollupState combines check states the way GitHub shows them on a commit.
+func rollupState(checks []model.CheckInfo) string {
+	if len(checks) == 0 {
+		return checkStateNone
+	}
+	state := checkStateSuccess
+	for _, check := range checks {
+		switch check.State {
+		case checkStateFailure:
+			return checkStateFailure
+		case checkStatePending:
+			state = checkStatePending
+		}
+	}
+	return state
+}
+
+// truncatedState is the rollup state of checks that were cut off at
+// maxCheckPages: checks left out may still be running or have failed, so
+// only a failure is certain.
+func truncatedState(state string) string {
+	if state == checkStateFailure {
+		return state
+	}
+	return checkStatePending
+}
+
+// commitChecks returns the commit statuses and check runs of ref. truncated
+// reports that more than maxCheckPages pages of either exist.
+func (c *GithubClient) commitChecks(ctx context.Context, owner, repo, ref string) (checks []model.CheckInfo, truncated bool, err error) {
+	checks = make([]model.CheckInfo, 0)
+
+	opts := &github.ListOptions{PerPage: 100}
+	for page := 0; page < maxCheckPages; page++ {
+		combined, resp, err := c.c.Repositories.GetCombinedStatus(ctx, owner, repo, ref, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, status := range combined.Statuses {
+			checks = append(checks, model.CheckInfo{
+				Kind:        "status",
+				Name:        status.GetContext(),
+				Status:      status.GetState(),
+				State:       commitStatusState(status.GetState()),
+				Description: status.GetDescription(),
+				StartedAt:   formatTimestamp(status.CreatedAt),
+				CompletedAt: formatTimestamp(status.UpdatedAt),
+				DetailsURL:  status.GetTargetURL(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		truncated = page == maxCheckPages-1
+		opts.Page = resp.NextPage
+	}
+
+	runOpts := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for page := 0; page < maxCheckPages; page++ {
+		runs, resp, err := c.c.Checks.ListCheckRunsForRef(ctx, owner, repo, ref, runOpts)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, run := range runs.CheckRuns {
+			checks = append(checks, model.CheckInfo{
+				Kind:        "check_run",
+				Name:        run.GetName(),
+				Status:      run.GetStatus(),
+				Conclusion:  run.GetConclusion(),
+				State:       checkRunState(run.GetStatus(), run.GetConclusion()),
+				Description: run.GetOutput().GetTitle(),
+				App:         run.GetApp().GetSlug(),
+				StartedAt:   formatTimestamp(run.StartedAt),
+				CompletedAt: formatTimestamp(run.CompletedAt),
+				DetailsURL:  run.GetDetailsURL(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		truncated = truncated || page == maxCheckPages-1
+		runOpts.Page = resp.NextPage
+	}
+
+	return checks, truncated, nil
+}
+
+// GetCommitChecks reports the commit statuses, check runs and check suites of
+// a ref together with an overall state.
+func (c *GithubClient) GetCommitChecks(ctx context.Context, opt model.GetCommitChecksOption) (*model.CommitChecksResult, error) {
+	checks, truncated, err := c.commitChecks(ctx, opt.Owner, opt.Repository, opt.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.CommitChecksResult{
+		Ref:          opt.Ref,
+		State:        rollupState(checks),
+		LimitReached: truncated,
+		Checks:       checks,
+		Suites:       make([]model.CheckSuiteInfo, 0),
+	}
+	if truncated {
+		result.State = truncatedState(result.State)
+	}
+	for _, check := range checks {
+		switch check.State {
+		case checkStateSuccess:
+			result.Success++
+		case checkStateFailure:
+			result.Failure++
+		case checkStatePending:
+			result.Pending++
+		}
+	}
+
+	// suites are reported but left out of the rollup, every installed app gets
+	// a suite even if it never creates a run
+	suiteOpts := &github.ListCheckSuiteOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for page := 0; page < maxCheckPages; page++ {
+		suites, resp, err := c.c.Checks.ListCheckSuitesForRef(ctx, opt.Owner, opt.Repository, opt.Ref, suiteOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, suite := range suites.CheckSuites {
+			result.Suites = append(result.Suites, model.CheckSuiteInfo{
+				ID:         suite.GetID(),
+				App:        suite.GetApp().GetSlug(),
+				HeadBranch: suite.GetHeadBranch(),
+				Status:     suite.GetStatus(),
+				Conclusion: suite.GetConclusion(),
+				CreatedAt:  formatTimestamp(suite.CreatedAt),
+				UpdatedAt:  formatTimestamp(suite.UpdatedAt),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		result.LimitReached = result.LimitReached || page == maxCheckPages-1
+		suiteOpts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+func newChecksTestClient(t *testing.T, statuses, runs []map[string]interface{}) *GithubClient {
+	t.Helper()
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/commits/main/status":
+			json.NewEncoder(w).Encode(map[string]interface{}{"state": "pending", "statuses": statuses})
+		case "/api/v3/repos/o/r/commits/main/check-runs":
+			// the second page is only reached through the Link header
+			if r.URL.Query().Get("page") == "2" {
+				json.NewEncoder(w).Encode(map[string]interface{}{"total_count": len(runs), "check_runs": runs[1:]})
+				return
+			}
+			if len(runs) > 1 {
+				w.Header().Set("Link", `<http://`+r.Host+r.URL.Path+`?page=2>; rel="next"`)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"total_count": len(runs), "check_runs": runs[:min(len(runs), 1)]})
+		case "/api/v3/repos/o/r/commits/main/check-suites":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"total_count":  1,
+				"check_suites": []map[string]interface{}{{"id": 9, "status": "queued", "head_branch": "main", "app": map[string]interface{}{"slug": "idle-app"}}},
+			})
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	})
+}
+
+// TestGetCommitChecks tests that statuses and check runs from all pages are rolled up
+func TestGetCommitChecks(t *testing.T) {
+	statuses := []map[string]interface{}{
+		{"context": "ci/legacy", "state": "success", "target_url": "https://ci.example.com/1", "created_at": "2024-05-01T10:00:00Z"},
+	}
+	runs := []map[string]interface{}{
+		{"name": "build", "status": "completed", "conclusion": "success", "details_url": "https://example.com/build", "app": map[string]interface{}{"slug": "github-actions"}},
+		{"name": "lint", "status": "completed", "conclusion": "skipped"},
+		{"name": "test", "status": "in_progress", "started_at": "2024-05-01T10:05:00Z"},
+	}
+	client := newChecksTestClient(t, statuses, runs)
+
+	checks, err := client.GetCommitChecks(context.Background(), model.GetCommitChecksOption{Owner: "o", Repository: "r", Ref: "main"})
+	if err != nil {
+		t.Fatalf("GetCommitChecks failed: %v", err)
+	}
+	if checks.State != "pending" || checks.Success != 3 || checks.Pending != 1 || len(checks.Checks) != 4 {
+		t.Errorf("Unexpected rollup: %+v", checks)
+	}
+	legacy := checks.Checks[0]
+	if legacy.Kind != "status" || legacy.Name != "ci/legacy" || legacy.DetailsURL != "https://ci.example.com/1" || legacy.StartedAt != "2024-05-01T10:00:00Z" {
+		t.Errorf("Unexpected status: %+v", legacy)
+	}
+	if build := checks.Checks[1]; build.Kind != "check_run" || build.App != "github-actions" || build.Conclusion != "success" {
+		t.Errorf("Unexpected check run: %+v", build)
+	}
+	if len(checks.Suites) != 1 || checks.Suites[0].App != "idle-app" {
+		t.Errorf("Unexpected suites: %+v", checks.Suites)
+	}
+}
+
+// TestGetCommitChecksLimit tests that checks cut off at the page limit are flagged and not reported as passing
+func TestGetCommitChecksLimit(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/commits/main/status":
+			json.NewEncoder(w).Encode(map[string]interface{}{"state": "success", "statuses": []map[string]interface{}{}})
+		case "/api/v3/repos/o/r/commits/main/check-runs":
+			writeEndlessPage(w, r, map[string]interface{}{
+				"total_count": 5000,
+				"check_runs":  []map[string]interface{}{{"name": "build", "status": "completed", "conclusion": "success"}},
+			})
+		case "/api/v3/repos/o/r/commits/main/check-suites":
+			json.NewEncoder(w).Encode(map[string]interface{}{"total_count": 0, "check_suites": []map[string]interface{}{}})
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	})
+
+	checks, err := client.GetCommitChecks(context.Background(), model.GetCommitChecksOption{Owner: "o", Repository: "r", Ref: "main"})
+	if err != nil {
+		t.Fatalf("GetCommitChecks failed: %v", err)
+	}
+	if !checks.LimitReached || checks.State != "pending" || len(checks.Checks) != maxCheckPages {
+		t.Errorf("Expected a truncated pending rollup of %d checks, got %v %s %d", maxCheckPages, checks.LimitReached, checks.State, len(checks.Checks))
+	}
+}
+
+func TestRollupState(t *testing.T) {
+	cases := []struct {
+		states []string
+		want   string
+	}{
+		{nil, "none"},
+		{[]string{"success", "success"}, "success"},
+		{[]string{"success", "pending"}, "pending"},
+		{[]string{"pending", "failure", "success"}, "failure"},
+	}
+	for _, c := range cases {
+		var checks []model.CheckInfo
+		for _, state := range c.states {
+			checks = append(checks, model.CheckInfo{State: state})
+		}
+		if got := rollupState(checks); got != c.want {
+			t.Errorf("rollupState(%v) = %s, want %s", c.states, got, c.want)
+		}
+	}
+
+	if checkRunState("completed", "timed_out") != "failure" || checkRunState("completed", "neutral") != "success" || checkRunState("queued", "") != "pending" {
+		t.Errorf("Unexpected check run states")
+	}
+	if commitStatusState("error") != "failure" {
+		t.Errorf("Expected an errored status to fail")
+	}
+}
@@ -1,44 +1,257 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Felamande/githubMcp/model"
 	"github.com/google/go-github/v74/github"
 )
 
+const (
+	defaultPageSize                = 10
+	defaultDescriptionTruncateSize = 1024
+)
+
 type GithubClient struct {
 	c *github.Client
+
+	pageSize                int
+	descriptionTruncateSize int
+
+	baseURL   string
+	uploadURL string
+	rateLimit rateLimitTransport
+
+	cacheSize int
+	cacheTTL  time.Duration
+	cacheDir  string
+	cache     *cacheTransport
+
+	app *appTokenSource
+
+	downloadDir    string
+	downloadDirSet bool
+	downloadMaxAge time.Duration
+	pruneMu        sync.Mutex
+	lastPrune      time.Time
+	download       *http.Client
+	downloadsMu    sync.Mutex
+	downloads      map[string]*downloadLock
+}
+
+// Option configures a GithubClient created by NewClient.
+type Option func(*GithubClient) error
+
+// WithEnterpriseURLs points the client at a GitHub Enterprise Server. The
+// api/v3 and api/uploads paths are appended when missing, an empty upload
+// URL defaults to the base URL.
+func WithEnterpriseURLs(baseURL, uploadURL string) Option {
+	return func(c *GithubClient) error {
+		if uploadURL == "" {
+			uploadURL = baseURL
+		}
+		c.baseURL = baseURL
+		c.uploadURL = uploadURL
+		return nil
+	}
+}
+
+// WithPageSize sets the page size used when a request does not specify one.
+func WithPageSize(size int) Option {
+	return func(c *GithubClient) error {
+		if size <= 0 {
+			return fmt.Errorf("page size must be positive, got %d", size)
+		}
+		c.pageSize = size
+		return nil
+	}
+}
+
+// WithDescriptionTruncateSize sets the default truncation size of long descriptions.
+func WithDescriptionTruncateSize(size int) Option {
+	return func(c *GithubClient) error {
+		if size <= 0 {
+			return fmt.Errorf("description truncate size must be positive, got %d", size)
+		}
+		c.descriptionTruncateSize = size
+		return nil
+	}
+}
+
+// WithRateLimitRetry sets how often a request hitting a secondary rate limit
+// is retried, and the longest the client waits before one retry.
+func WithRateLimitRetry(maxRetries int, maxWait time.Duration) Option {
+	return func(c *GithubClient) error {
+		if maxRetries < 0 || maxWait < 0 {
+			return fmt.Errorf("rate limit retries and wait must not be negative")
+		}
+		c.rateLimit.maxRetries = maxRetries
+		c.rateLimit.maxWait = maxWait
+		return nil
+	}
 }
 
-func NewClient(token string) *GithubClient {
-	client := github.NewClient(nil)
+// WithCache keeps up to maxEntries responses and revalidates them with
+// conditional requests. Entries not validated within ttl are dropped, a zero
+// ttl keeps them until evicted. When dir is not empty entries are also stored
+// on disk and survive restarts.
+func WithCache(maxEntries int, ttl time.Duration, dir string) Option {
+	return func(c *GithubClient) error {
+		if maxEntries <= 0 {
+			return fmt.Errorf("cache size must be positive, got %d", maxEntries)
+		}
+		if ttl < 0 {
+			return fmt.Errorf("cache ttl must not be negative, got %s", ttl)
+		}
+		c.cacheSize = maxEntries
+		c.cacheTTL = ttl
+		c.cacheDir = dir
+		return nil
+	}
+}
+
+// WithDownloadDir sets the directory repository archives and other downloads
+// are kept in, it defaults to a directory below the system temp dir.
+func WithDownloadDir(dir string) Option {
+	return func(c *GithubClient) error {
+		if dir == "" {
+			return fmt.Errorf("download dir must not be empty")
+		}
+		c.downloadDir = dir
+		c.downloadDirSet = true
+		return nil
+	}
+}
+
+// WithDownloadMaxAge sets how long a download is kept in the download
+// directory without being used, zero keeps downloads forever.
+func WithDownloadMaxAge(age time.Duration) Option {
+	return func(c *GithubClient) error {
+		if age < 0 {
+			return fmt.Errorf("download max age must not be negative, got %s", age)
+		}
+		c.downloadMaxAge = age
+		return nil
+	}
+}
+
+// WithAppAuth authenticates as an installation of a GitHub App instead of
+// with a static token. Installation tokens are minted with the App private
+// key and renewed before they expire.
+func WithAppAuth(appID, installationID int64, privateKeyPEM []byte) Option {
+	return func(c *GithubClient) error {
+		if appID <= 0 || installationID <= 0 {
+			return fmt.Errorf("app id and installation id must be positive")
+		}
+		key, err := parseAppPrivateKey(privateKeyPEM)
+		if err != nil {
+			return err
+		}
+		c.app = &appTokenSource{
+			appID:          appID,
+			installationID: installationID,
+			key:            key,
+			now:            time.Now,
+		}
+		return nil
+	}
+}
+
+func NewClient(token string, opts ...Option) (*GithubClient, error) {
+	c := &GithubClient{
+		pageSize:                defaultPageSize,
+		descriptionTruncateSize: defaultDescriptionTruncateSize,
+		rateLimit: rateLimitTransport{
+			maxRetries: defaultRateLimitRetries,
+			maxWait:    defaultRateLimitMaxWait,
+		},
+		downloadDir:    filepath.Join(os.TempDir(), "githubmcp-downloads"),
+		downloadMaxAge: defaultDownloadMaxAge,
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if token != "" && c.app != nil {
+		return nil, fmt.Errorf("a token and app authentication cannot be used together")
+	}
+
+	c.rateLimit.base = http.DefaultTransport
+	c.download = &http.Client{Transport: &c.rateLimit}
+	var transport http.RoundTripper = &c.rateLimit
+	if c.cacheSize > 0 {
+		c.cache = newCacheTransport(transport, c.cacheSize, c.cacheTTL, c.cacheDir)
+		transport = c.cache
+	}
+	if c.app != nil {
+		appClient, err := c.newGithubClient(&appJWTTransport{base: &c.rateLimit, source: c.app})
+		if err != nil {
+			return nil, err
+		}
+		c.app.apps = appClient.Apps
+		transport = &appInstallationTransport{base: transport, source: c.app}
+	}
+
+	client, err := c.newGithubClient(transport)
+	if err != nil {
+		return nil, err
+	}
 	if token != "" {
 		client = client.WithAuthToken(token)
 	}
-	return &GithubClient{
-		c: client,
+	c.c = client
+	// the default download dir is shared by every process on the host, it
+	// is only pruned once this client downloads something
+	if c.downloadDirSet {
+		c.startPrune()
+	}
+	return c, nil
+}
+
+func (c *GithubClient) newGithubClient(transport http.RoundTripper) (*github.Client, error) {
+	client := github.NewClient(&http.Client{Transport: transport})
+	if c.baseURL == "" {
+		return client, nil
+	}
+	client, err := client.WithEnterpriseURLs(c.baseURL, c.uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enterprise url: %v", err)
 	}
+	return client, nil
 }
 
-func (c *GithubClient) GetRepository(opt model.SearchOption) (r *model.SearchResult, err error) {
+func (c *GithubClient) GetRepository(ctx context.Context, opt model.SearchOption) (r *model.SearchResult, err error) {
 
 	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
+		opt.ResultPerpage = c.pageSize
 	}
 	if opt.Page == 0 {
 		opt.Page = 1
 	}
 
 	if opt.DescriptionTruncateSize == 0 {
-		opt.DescriptionTruncateSize = 1024
+		opt.DescriptionTruncateSize = c.descriptionTruncateSize
 	}
 
-	ctx := context.Background()
 	opts := &github.SearchOptions{
 		Sort:  opt.Sort,  // 按星标数排序
 		Order: opt.Order, // 降序排列
@@ -91,15 +304,15 @@ func (c *GithubClient) GetRepository(opt model.SearchOption) (r *model.SearchRes
 	return searches, nil
 }
 
-func (c *GithubClient) ListReleases(opt model.ReleaseListOption) (*model.ReleaseListResult, error) {
+func (c *GithubClient) ListReleases(ctx context.Context, opt model.ReleaseListOption) (*model.ReleaseListResult, error) {
 	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
+		opt.ResultPerpage = c.pageSize
 	}
 	if opt.Page == 0 {
 		opt.Page = 1
 	}
 	if opt.DescriptionTruncateSize == 0 {
-		opt.DescriptionTruncateSize = 1024
+		opt.DescriptionTruncateSize = c.descriptionTruncateSize
 	}
 	opts := &github.ListOptions{
 		PerPage: opt.ResultPerpage, // 每页显示 10 个结果
@@ -107,7 +320,7 @@ func (c *GithubClient) ListReleases(opt model.ReleaseListOption) (*model.Release
 	}
 
 	// 获取 releases 列表
-	releases, resp, err := c.c.Repositories.ListReleases(context.Background(), opt.Owner, opt.Repository, opts)
+	releases, resp, err := c.c.Repositories.ListReleases(ctx, opt.Owner, opt.Repository, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -149,12 +362,11 @@ func (c *GithubClient) ListReleases(opt model.ReleaseListOption) (*model.Release
 	return releasesResult, nil
 }
 
-func (c *GithubClient) GetReadme(opt model.ReadmeOption) (*model.ReadmeResult, error) {
+func (c *GithubClient) GetReadme(ctx context.Context, opt model.ReadmeOption) (*model.ReadmeResult, error) {
 	if opt.StartLine == 0 {
 		opt.StartLine = 1
 	}
 
-	ctx := context.Background()
 	contentGetOptions := (*github.RepositoryContentGetOptions)(nil)
 	if opt.Ref != "" {
 		contentGetOptions = &github.RepositoryContentGetOptions{
@@ -198,15 +410,14 @@ func (c *GithubClient) GetReadme(opt model.ReadmeOption) (*model.ReadmeResult, e
 	return result, nil
 }
 
-func (c *GithubClient) ListTags(opt model.TagListOption) (*model.TagListResult, error) {
+func (c *GithubClient) ListTags(ctx context.Context, opt model.TagListOption) (*model.TagListResult, error) {
 	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
+		opt.ResultPerpage = c.pageSize
 	}
 	if opt.Page == 0 {
 		opt.Page = 1
 	}
 
-	ctx := context.Background()
 	opts := &github.ListOptions{
 		PerPage: opt.ResultPerpage,
 		Page:    opt.Page,
@@ -239,23 +450,76 @@ func (c *GithubClient) ListTags(opt model.TagListOption) (*model.TagListResult,
 	return result, nil
 }
 
-func (c *GithubClient) ListCommits(opt model.CommitListOption) (*model.CommitListResult, error) {
+// parseTimestamp parses an RFC 3339 timestamp or a plain date, which is
+// taken as midnight UTC. An empty value returns the zero time.
+func parseTimestamp(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %q: expected an RFC 3339 timestamp like 2024-01-02T15:04:05Z or a date like 2024-01-02", name, value)
+}
+
+// parseUntil parses the end of a time range like parseTimestamp, except that
+// a plain date covers the whole day and is taken as its last second.
+func parseUntil(name, value string) (time.Time, error) {
+	t, err := parseTimestamp(name, value)
+	if err != nil {
+		return t, err
+	}
+	if _, err := time.Parse(time.DateOnly, value); err == nil {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return t, nil
+}
+
+func (c *GithubClient) ListCommits(ctx context.Context, opt model.CommitListOption) (*model.CommitListResult, error) {
 	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
+		opt.ResultPerpage = c.pageSize
 	}
 	if opt.Page == 0 {
 		opt.Page = 1
 	}
 
-	ctx := context.Background()
-	opts := &github.CommitsListOptions{
-		ListOptions: github.ListOptions{
-			PerPage: opt.ResultPerpage,
-			Page:    opt.Page,
-		},
+	since, err := parseTimestamp("since", opt.Since)
+	if err != nil {
+		return nil, err
+	}
+	until, err := parseUntil("until", opt.Until)
+	if err != nil {
+		return nil, err
+	}
+	if !since.IsZero() && !until.IsZero() && since.After(until) {
+		return nil, fmt.Errorf("since %s is after until %s", opt.Since, opt.Until)
+	}
+
+	// built by hand as CommitsListOptions has no committer filter
+	query := url.Values{}
+	for name, value := range map[string]string{"sha": opt.SHA, "path": opt.Path, "author": opt.Author, "committer": opt.Committer} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if !since.IsZero() {
+		query.Set("since", since.Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		query.Set("until", until.Format(time.RFC3339))
 	}
+	query.Set("per_page", strconv.Itoa(opt.ResultPerpage))
+	query.Set("page", strconv.Itoa(opt.Page))
 
-	commits, resp, err := c.c.Repositories.ListCommits(ctx, opt.Owner, opt.Repository, opts)
+	req, err := c.c.NewRequest("GET", fmt.Sprintf("repos/%s/%s/commits?%s", opt.Owner, opt.Repository, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	var commits []*github.RepositoryCommit
+	resp, err := c.c.Do(ctx, req, &commits)
 	if err != nil {
 		return nil, err
 	}
@@ -298,9 +562,71 @@ func (c *GithubClient) ListCommits(opt model.CommitListOption) (*model.CommitLis
 	return result, nil
 }
 
-func (c *GithubClient) GetCommitBySHA(opt model.GetCommitBySHAOption) (*model.CommitInfo, error) {
-	ctx := context.Background()
+func (c *GithubClient) SearchCommits(ctx context.Context, opt model.SearchCommitsOption) (*model.SearchCommitsResult, error) {
+	if opt.ResultPerpage == 0 {
+		opt.ResultPerpage = c.pageSize
+	}
+	if opt.Page == 0 {
+		opt.Page = 1
+	}
+
+	opts := &github.SearchOptions{
+		Sort:  opt.Sort,
+		Order: opt.Order,
+		ListOptions: github.ListOptions{
+			PerPage: opt.ResultPerpage,
+			Page:    opt.Page,
+		},
+	}
+
+	result, resp, err := c.c.Search.Commits(ctx, opt.Query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	searchResult := &model.SearchCommitsResult{
+		TotalCount: result.GetTotal(),
+		NextPage:   resp.NextPage,
+		LastPage:   resp.LastPage,
+		Commits:    make([]model.CommitSearchInfo, 0),
+	}
+
+	for _, commitResult := range result.Commits {
+		commitInfo := model.CommitSearchInfo{
+			CommitInfo: model.CommitInfo{
+				SHA:              commitResult.GetSHA(),
+				URL:              commitResult.GetHTMLURL(),
+				ParentCommitHash: make([]string, 0),
+			},
+			Owner:      commitResult.GetRepository().GetOwner().GetLogin(),
+			Repository: commitResult.GetRepository().GetName(),
+		}
+
+		if commit := commitResult.GetCommit(); commit != nil {
+			commitInfo.Message = commit.GetMessage()
+
+			if author := commit.GetAuthor(); author != nil {
+				commitInfo.Author = author.GetName()
+				commitInfo.AuthorEmail = author.GetEmail()
+				commitInfo.Date = author.GetDate().Format(time.RFC3339)
+			}
+			if committer := commit.GetCommitter(); committer != nil {
+				commitInfo.Committer = committer.GetName()
+				commitInfo.CommitterEmail = committer.GetEmail()
+			}
+		}
+
+		for _, parent := range commitResult.Parents {
+			commitInfo.ParentCommitHash = append(commitInfo.ParentCommitHash, parent.GetSHA())
+		}
+
+		searchResult.Commits = append(searchResult.Commits, commitInfo)
+	}
+
+	return searchResult, nil
+}
 
+func (c *GithubClient) GetCommitBySHA(ctx context.Context, opt model.GetCommitBySHAOption) (*model.CommitInfo, error) {
 	commitResult, _, err := c.c.Repositories.GetCommit(ctx, opt.Owner, opt.Repository, opt.SHA, nil)
 	if err != nil {
 		return nil, err
@@ -335,9 +661,23 @@ func (c *GithubClient) GetCommitBySHA(opt model.GetCommitBySHAOption) (*model.Co
 	return commitInfo, nil
 }
 
-func (c *GithubClient) GetCommitFilesBySHA(opt model.GetCommitFilesBySHAOption) (*model.CommitFilesResult, error) {
-	ctx := context.Background()
+func commitFileInfo(file *github.CommitFile) model.CommitFileInfo {
+	return model.CommitFileInfo{
+		SHA:              file.GetSHA(),
+		Filename:         file.GetFilename(),
+		Additions:        file.GetAdditions(),
+		Deletions:        file.GetDeletions(),
+		Changes:          file.GetChanges(),
+		Status:           file.GetStatus(),
+		Patch:            file.GetPatch(),
+		BlobURL:          file.GetBlobURL(),
+		RawURL:           file.GetRawURL(),
+		ContentsURL:      file.GetContentsURL(),
+		PreviousFilename: file.GetPreviousFilename(),
+	}
+}
 
+func (c *GithubClient) GetCommitFilesBySHA(ctx context.Context, opt model.GetCommitFilesBySHAOption) (*model.CommitFilesResult, error) {
 	commitResult, _, err := c.c.Repositories.GetCommit(ctx, opt.Owner, opt.Repository, opt.SHA, nil)
 	if err != nil {
 		return nil, err
@@ -349,34 +689,20 @@ func (c *GithubClient) GetCommitFilesBySHA(opt model.GetCommitFilesBySHAOption)
 
 	// Process file information
 	for _, file := range commitResult.Files {
-		fileInfo := model.CommitFileInfo{
-			SHA:              file.GetSHA(),
-			Filename:         file.GetFilename(),
-			Additions:        file.GetAdditions(),
-			Deletions:        file.GetDeletions(),
-			Changes:          file.GetChanges(),
-			Status:           file.GetStatus(),
-			Patch:            file.GetPatch(),
-			BlobURL:          file.GetBlobURL(),
-			RawURL:           file.GetRawURL(),
-			ContentsURL:      file.GetContentsURL(),
-			PreviousFilename: file.GetPreviousFilename(),
-		}
-		result.Files = append(result.Files, fileInfo)
+		result.Files = append(result.Files, commitFileInfo(file))
 	}
 
 	return result, nil
 }
 
-func (c *GithubClient) ListBranches(opt model.BranchListOption) (*model.BranchListResult, error) {
+func (c *GithubClient) ListBranches(ctx context.Context, opt model.BranchListOption) (*model.BranchListResult, error) {
 	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
+		opt.ResultPerpage = c.pageSize
 	}
 	if opt.Page == 0 {
 		opt.Page = 1
 	}
 
-	ctx := context.Background()
 	opts := &github.BranchListOptions{
 		ListOptions: github.ListOptions{
 			PerPage: opt.ResultPerpage,
@@ -412,9 +738,7 @@ func (c *GithubClient) ListBranches(opt model.BranchListOption) (*model.BranchLi
 	return result, nil
 }
 
-func (c *GithubClient) ListDirectory(opt model.DirectoryListOption) (*model.DirectoryListResult, error) {
-	ctx := context.Background()
-
+func (c *GithubClient) ListDirectory(ctx context.Context, opt model.DirectoryListOption) (*model.DirectoryListResult, error) {
 	// Set up options with ref if provided
 	contentGetOptions := (*github.RepositoryContentGetOptions)(nil)
 	if opt.Ref != "" {
@@ -441,18 +765,93 @@ func (c *GithubClient) ListDirectory(opt model.DirectoryListOption) (*model.Dire
 			Size:     int64(content.GetSize()),
 			Type:     content.GetType(),
 			Encoding: content.GetEncoding(),
+			SHA:      content.GetSHA(),
+		})
+	}
+
+	return result, nil
+}
+
+func (c *GithubClient) GetTree(ctx context.Context, opt model.GetTreeOption) (*model.TreeResult, error) {
+	if opt.Ref == "" {
+		opt.Ref = "HEAD"
+	}
+	if opt.MaxEntries == 0 {
+		opt.MaxEntries = 1000
+	}
+	prefix := strings.Trim(opt.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	tree, _, err := c.c.Git.GetTree(ctx, opt.Owner, opt.Repository, opt.Ref, true)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.TreeResult{
+		SHA:       tree.GetSHA(),
+		Truncated: tree.GetTruncated(),
+		Entries:   make([]model.DirectoryOrFileInfo, 0),
+	}
+
+	for _, entry := range tree.Entries {
+		entryPath := entry.GetPath()
+		if !strings.HasPrefix(entryPath, prefix) {
+			continue
+		}
+		relPath := strings.TrimPrefix(entryPath, prefix)
+		if opt.MaxDepth > 0 && strings.Count(relPath, "/") >= opt.MaxDepth {
+			continue
+		}
+		if opt.Pattern != "" {
+			matched, err := matchGlob(opt.Pattern, relPath)
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob pattern: %v", err)
+			}
+			if !matched {
+				continue
+			}
+		}
+
+		result.TotalMatches++
+		if len(result.Entries) >= opt.MaxEntries {
+			result.EntryLimitReached = true
+			continue
+		}
+		result.Entries = append(result.Entries, model.DirectoryOrFileInfo{
+			Name: path.Base(entryPath),
+			Path: entryPath,
+			Size: int64(entry.GetSize()),
+			Type: treeEntryType(entry),
+			SHA:  entry.GetSHA(),
 		})
 	}
 
 	return result, nil
 }
 
-func (c *GithubClient) ReadFile(opt model.ReadFileOption) (*model.ReadFileResult, error) {
+// treeEntryType maps git object types to the types used by the contents API.
+func treeEntryType(entry *github.TreeEntry) string {
+	switch {
+	case entry.GetType() == "tree":
+		return "dir"
+	case entry.GetType() == "commit":
+		return "submodule"
+	case entry.GetMode() == "120000":
+		return "symlink"
+	default:
+		return "file"
+	}
+}
+
+func (c *GithubClient) ReadFile(ctx context.Context, opt model.ReadFileOption) (*model.ReadFileResult, error) {
 	if opt.StartLine == 0 {
 		opt.StartLine = 1
 	}
-
-	ctx := context.Background()
+	if opt.StartByte < 0 || (opt.EndByte > 0 && opt.EndByte <= opt.StartByte) {
+		return nil, fmt.Errorf("invalid byte range: %d-%d", opt.StartByte, opt.EndByte)
+	}
 
 	// Set up options with ref if provided
 	contentGetOptions := (*github.RepositoryContentGetOptions)(nil)
@@ -473,12 +872,68 @@ func (c *GithubClient) ReadFile(opt model.ReadFileOption) (*model.ReadFileResult
 		return nil, fmt.Errorf("path is not a file: %s", opt.Path)
 	}
 
-	content, err := fileContent.GetContent()
+	data, err := c.fileData(ctx, opt, fileContent)
 	if err != nil {
 		return nil, err
 	}
 
-	lines := strings.Split(content, "\n")
+	result := fileResult(opt.Path, data, opt)
+	result.SHA = fileContent.GetSHA()
+	if result.Encoding == "" {
+		result.Encoding = fileContent.GetEncoding()
+	}
+
+	return result, nil
+}
+
+// fileResult selects the requested lines or bytes of data. Binary content is
+// described by its MIME type and sha256 instead of being split into lines.
+func fileResult(name string, data []byte, opt model.ReadFileOption) *model.ReadFileResult {
+	result := &model.ReadFileResult{
+		Size:   len(data),
+		Binary: isBinary(data),
+	}
+	if result.Binary {
+		sum := sha256.Sum256(data)
+		result.SHA256 = hex.EncodeToString(sum[:])
+		result.MIMEType = detectMIMEType(name, data)
+	}
+
+	if opt.StartByte > 0 || opt.EndByte > 0 {
+		if opt.StartByte > len(data) {
+			opt.StartByte = len(data)
+		}
+		if opt.EndByte == 0 || opt.EndByte > len(data) {
+			opt.EndByte = len(data)
+		}
+		// the binary check above saw the whole blob, a text range is widened
+		// to whole runes so that it never splits a UTF-8 sequence
+		if !result.Binary {
+			for opt.StartByte > 0 && opt.StartByte < len(data) && !utf8.RuneStart(data[opt.StartByte]) {
+				opt.StartByte--
+			}
+			for opt.EndByte < len(data) && !utf8.RuneStart(data[opt.EndByte]) {
+				opt.EndByte++
+			}
+		}
+		result.StartByte = opt.StartByte
+		result.EndByte = opt.EndByte
+		if result.Binary {
+			result.Content = base64.StdEncoding.EncodeToString(data[opt.StartByte:opt.EndByte])
+			result.Encoding = "base64"
+		} else {
+			result.Content = string(data[opt.StartByte:opt.EndByte])
+			result.Encoding = "utf-8"
+		}
+		return result
+	}
+
+	// splitting binary content on newlines only produces garbage
+	if result.Binary {
+		return result
+	}
+
+	lines := strings.Split(string(data), "\n")
 	totalLines := len(lines)
 
 	if opt.EndLine == 0 || opt.EndLine > totalLines {
@@ -488,27 +943,138 @@ func (c *GithubClient) ReadFile(opt model.ReadFileOption) (*model.ReadFileResult
 	if opt.StartLine < 1 {
 		opt.StartLine = 1
 	}
+	// a range starting past the end of the file is empty
+	if opt.StartLine > totalLines {
+		result.TotalLines = totalLines
+		return result
+	}
 	if opt.EndLine < opt.StartLine {
 		opt.EndLine = opt.StartLine
 	}
 
 	selectedLines := lines[opt.StartLine-1 : opt.EndLine]
-	selectedContent := strings.Join(selectedLines, "\n")
+	result.Content = strings.Join(selectedLines, "\n")
+	result.StartLine = opt.StartLine
+	result.EndLine = opt.EndLine
+	result.TotalLines = totalLines
 
-	result := &model.ReadFileResult{
-		Content:    selectedContent,
-		StartLine:  opt.StartLine,
-		EndLine:    opt.EndLine,
-		TotalLines: totalLines,
-		Encoding:   fileContent.GetEncoding(),
+	return result
+}
+
+// fileData returns the content of a file. The contents API leaves out the
+// content of files over 1 MB, those are fetched as raw git blobs instead.
+func (c *GithubClient) fileData(ctx context.Context, opt model.ReadFileOption, fileContent *github.RepositoryContent) ([]byte, error) {
+	if fileContent.GetEncoding() != "none" && (fileContent.Content != nil || fileContent.GetSize() == 0) {
+		content, err := fileContent.GetContent()
+		if err != nil {
+			return nil, err
+		}
+		return []byte(content), nil
 	}
 
-	return result, nil
+	data, _, err := c.c.Git.GetBlobRaw(ctx, opt.Owner, opt.Repository, fileContent.GetSHA())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s: %w", fileContent.GetSHA(), err)
+	}
+	return data, nil
+}
+
+// isBinary reports whether data looks like binary content, using the same
+// NUL byte heuristic as git followed by a UTF-8 check.
+func isBinary(data []byte) bool {
+	sample := data
+	if len(sample) > 8000 {
+		sample = sample[:8000]
+	}
+	return bytes.IndexByte(sample, 0) >= 0 || !utf8.Valid(data)
+}
+
+func detectMIMEType(name string, data []byte) string {
+	if mimeType := mime.TypeByExtension(path.Ext(name)); mimeType != "" {
+		return mimeType
+	}
+	return http.DetectContentType(data)
 }
 
-func (c *GithubClient) FindTags(opt model.FindTagsOption) (*model.FindTagsResult, error) {
-	ctx := context.Background()
+const (
+	readFilesWorkers          = 4
+	maxReadFiles              = 50
+	defaultReadFilesMaxOutput = 200000
+)
+
+// ReadFiles reads several files of one ref concurrently. Files failing to
+// read are reported with their error instead of failing the whole call, and
+// once the output budget is spent the remaining files are returned without
+// their content.
+func (c *GithubClient) ReadFiles(ctx context.Context, opt model.ReadFilesOption) (*model.ReadFilesResult, error) {
+	if len(opt.Files) == 0 {
+		return nil, fmt.Errorf("no files to read")
+	}
+	if len(opt.Files) > maxReadFiles {
+		return nil, fmt.Errorf("at most %d files can be read at once, got %d", maxReadFiles, len(opt.Files))
+	}
+	if opt.MaxOutputSize == 0 {
+		opt.MaxOutputSize = defaultReadFilesMaxOutput
+	}
+
+	entries := make([]model.ReadFilesEntry, len(opt.Files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(readFilesWorkers, len(opt.Files)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				file := opt.Files[i]
+				entries[i].Path = file.Path
+				result, err := c.ReadFile(ctx, model.ReadFileOption{
+					Owner:      opt.Owner,
+					Repository: opt.Repository,
+					Path:       file.Path,
+					Ref:        opt.Ref,
+					StartLine:  file.StartLine,
+					EndLine:    file.EndLine,
+				})
+				if err != nil {
+					entries[i].Error = err.Error()
+					continue
+				}
+				entries[i].File = result
+			}
+		}()
+	}
+	for i := range opt.Files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// the budget is applied in request order so the result does not depend
+	// on which worker finished first
+	result := &model.ReadFilesResult{Files: entries}
+	remaining := opt.MaxOutputSize
+	for i := range entries {
+		file := entries[i].File
+		if file == nil {
+			continue
+		}
+		if len(file.Content) > remaining {
+			file.Content = ""
+			entries[i].OverBudget = true
+			result.BudgetExceeded = true
+			continue
+		}
+		remaining -= len(file.Content)
+	}
+
+	return result, nil
+}
 
+func (c *GithubClient) FindTags(ctx context.Context, opt model.FindTagsOption) (*model.FindTagsResult, error) {
 	tags, _, err := c.c.Repositories.ListTags(ctx, opt.Owner, opt.Repository, &github.ListOptions{
 		PerPage: 1000, // Get more tags to search through
 	})
@@ -543,9 +1109,7 @@ func (c *GithubClient) FindTags(opt model.FindTagsOption) (*model.FindTagsResult
 	return result, nil
 }
 
-func (c *GithubClient) GetTagByName(opt model.GetTagByNameOption) (*model.TagInfo, error) {
-	ctx := context.Background()
-
+func (c *GithubClient) GetTagByName(ctx context.Context, opt model.GetTagByNameOption) (*model.TagInfo, error) {
 	tags, _, err := c.c.Repositories.ListTags(ctx, opt.Owner, opt.Repository, &github.ListOptions{
 		PerPage: 100, // Get a reasonable number of tags to search through
 	})
@@ -570,9 +1134,7 @@ func (c *GithubClient) GetTagByName(opt model.GetTagByNameOption) (*model.TagInf
 	return nil, fmt.Errorf("tag '%s' not found in repository %s/%s", opt.TagName, opt.Owner, opt.Repository)
 }
 
-func (c *GithubClient) FindBranches(opt model.FindBranchesOption) (*model.FindBranchesResult, error) {
-	ctx := context.Background()
-
+func (c *GithubClient) FindBranches(ctx context.Context, opt model.FindBranchesOption) (*model.FindBranchesResult, error) {
 	branches, _, err := c.c.Repositories.ListBranches(ctx, opt.Owner, opt.Repository, &github.BranchListOptions{
 		ListOptions: github.ListOptions{
 			PerPage: 1000, // Get more branches to search through
@@ -608,9 +1170,7 @@ func (c *GithubClient) FindBranches(opt model.FindBranchesOption) (*model.FindBr
 	return result, nil
 }
 
-func (c *GithubClient) GetBranchByName(opt model.GetBranchByNameOption) (*model.BranchInfo, error) {
-	ctx := context.Background()
-
+func (c *GithubClient) GetBranchByName(ctx context.Context, opt model.GetBranchByNameOption) (*model.BranchInfo, error) {
 	branch, _, err := c.c.Repositories.GetBranch(ctx, opt.Owner, opt.Repository, opt.BranchName, 5)
 	if err != nil {
 		return nil, err
@@ -627,15 +1187,14 @@ func (c *GithubClient) GetBranchByName(opt model.GetBranchByNameOption) (*model.
 
 }
 
-func (c *GithubClient) SearchCode(opt model.SearchCodeOption) (*model.SearchCodeResult, error) {
+func (c *GithubClient) SearchCode(ctx context.Context, opt model.SearchCodeOption) (*model.SearchCodeResult, error) {
 	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
+		opt.ResultPerpage = c.pageSize
 	}
 	if opt.Page == 0 {
 		opt.Page = 1
 	}
 
-	ctx := context.Background()
 	opts := &github.SearchOptions{
 		Sort:  opt.Sort,
 		Order: opt.Order,
@@ -693,9 +1252,9 @@ func (c *GithubClient) SearchCode(opt model.SearchCodeOption) (*model.SearchCode
 	return searchResult, nil
 }
 
-func (c *GithubClient) ListIssues(opt model.ListIssuesOption) (*model.IssuesListResult, error) {
+func (c *GithubClient) ListIssues(ctx context.Context, opt model.ListIssuesOption) (*model.IssuesListResult, error) {
 	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
+		opt.ResultPerpage = c.pageSize
 	}
 	if opt.Page == 0 {
 		opt.Page = 1
@@ -704,7 +1263,6 @@ func (c *GithubClient) ListIssues(opt model.ListIssuesOption) (*model.IssuesList
 		opt.State = "open"
 	}
 
-	ctx := context.Background()
 	opts := &github.IssueListByRepoOptions{
 		State:     opt.State,
 		Labels:    opt.Labels,
@@ -797,15 +1355,14 @@ func (c *GithubClient) ListIssues(opt model.ListIssuesOption) (*model.IssuesList
 	return result, nil
 }
 
-func (c *GithubClient) SearchIssues(opt model.SearchIssuesOption) (*model.IssuesListResult, error) {
+func (c *GithubClient) SearchIssues(ctx context.Context, opt model.SearchIssuesOption) (*model.IssuesListResult, error) {
 	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
+		opt.ResultPerpage = c.pageSize
 	}
 	if opt.Page == 0 {
 		opt.Page = 1
 	}
 
-	ctx := context.Background()
 	opts := &github.SearchOptions{
 		Sort:  opt.Sort,
 		Order: opt.Order,
@@ -864,9 +1421,9 @@ func (c *GithubClient) SearchIssues(opt model.SearchIssuesOption) (*model.Issues
 	return searchResult, nil
 }
 
-func (c *GithubClient) ListIssueComments(opt model.ListIssueCommentsOption) (*model.IssueCommentsResult, error) {
+func (c *GithubClient) ListIssueComments(ctx context.Context, opt model.ListIssueCommentsOption) (*model.IssueCommentsResult, error) {
 	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
+		opt.ResultPerpage = c.pageSize
 	}
 	if opt.Page == 0 {
 		opt.Page = 1
@@ -874,7 +1431,6 @@ func (c *GithubClient) ListIssueComments(opt model.ListIssueCommentsOption) (*mo
 	commentsResult := &model.IssueCommentsResult{}
 	commentsResult.Comments = make([]model.IssueCommentInfo, 0)
 
-	ctx := context.Background()
 	opts := &github.IssueListCommentsOptions{
 		ListOptions: github.ListOptions{
 			PerPage: opt.ResultPerpage,
@@ -917,15 +1473,14 @@ func (c *GithubClient) ListIssueComments(opt model.ListIssueCommentsOption) (*mo
 	return commentsResult, nil
 }
 
-func (c *GithubClient) ListIssueLabels(opt model.ListIssueLabelsOption) (*model.LableListResult, error) {
+func (c *GithubClient) ListIssueLabels(ctx context.Context, opt model.ListIssueLabelsOption) (*model.LableListResult, error) {
 	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
+		opt.ResultPerpage = c.pageSize
 	}
 	if opt.Page == 0 {
 		opt.Page = 1
 	}
 
-	ctx := context.Background()
 	opts := &github.ListOptions{
 		PerPage: opt.ResultPerpage,
 		Page:    opt.Page,
@@ -954,9 +1509,7 @@ func (c *GithubClient) ListIssueLabels(opt model.ListIssueLabelsOption) (*model.
 	return lableListResult, nil
 }
 
-func (c *GithubClient) GetIssueByNumber(opt model.GetIssueByNumberOption) (*model.IssueInfo, error) {
-	ctx := context.Background()
-
+func (c *GithubClient) GetIssueByNumber(ctx context.Context, opt model.GetIssueByNumberOption) (*model.IssueInfo, error) {
 	issue, _, err := c.c.Issues.Get(ctx, opt.Owner, opt.Repository, opt.IssueNumber)
 	if err != nil {
 		return nil, err
@@ -1020,126 +1573,8 @@ func (c *GithubClient) GetIssueByNumber(opt model.GetIssueByNumberOption) (*mode
 	return issueInfo, nil
 }
 
-func (c *GithubClient) ListPullRequests(opt model.ListPROption) (*model.PRListResult, error) {
-	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
-	}
-	if opt.Page == 0 {
-		opt.Page = 1
-	}
-	if opt.State == "" {
-		opt.State = "open"
-	}
-
-	ctx := context.Background()
-	opts := &github.PullRequestListOptions{
-		State:     opt.State,
-		Head:      opt.Head,
-		Base:      opt.Base,
-		Sort:      opt.Sort,
-		Direction: opt.Direction,
-		ListOptions: github.ListOptions{
-			PerPage: opt.ResultPerpage,
-			Page:    opt.Page,
-		},
-	}
-
-	prs, resp, err := c.c.PullRequests.List(ctx, opt.Owner, opt.Repository, opts)
-	if err != nil {
-		return nil, err
-	}
-
-	result := &model.PRListResult{
-		TotalCount: len(prs),
-		NextPage:   resp.NextPage,
-		LastPage:   resp.LastPage,
-		PRs:        make([]model.PRInfo, 0),
-	}
-
-	for _, pr := range prs {
-		prInfo := model.PRInfo{
-			Number:         pr.GetNumber(),
-			Title:          pr.GetTitle(),
-			State:          pr.GetState(),
-			Body:           pr.GetBody(),
-			Comments:       pr.GetComments(),
-			Additions:      pr.GetAdditions(),
-			Deletions:      pr.GetDeletions(),
-			ChangedFiles:   pr.GetChangedFiles(),
-			Mergeable:      pr.GetMergeable(),
-			MergeableState: pr.GetMergeableState(),
-			Merged:         pr.GetMerged(),
-			BaseRef:        pr.Base.GetRef(),
-			HeadRef:        pr.Head.GetRef(),
-			Draft:          pr.GetDraft(),
-			ReviewComments: pr.GetReviewComments(),
-			Commits:        pr.GetCommits(),
-			CreatedAt:      pr.GetCreatedAt().Format(time.RFC3339),
-			UpdatedAt:      pr.GetUpdatedAt().Format(time.RFC3339),
-			URL:            pr.GetURL(),
-			HTMLURL:        pr.GetHTMLURL(),
-		}
-
-		if pr.ClosedAt != nil {
-			prInfo.ClosedAt = pr.ClosedAt.Format(time.RFC3339)
-		}
-		if pr.MergedAt != nil {
-			prInfo.MergedAt = pr.MergedAt.Format(time.RFC3339)
-		}
-
-		// Process labels
-		for _, label := range pr.Labels {
-			prInfo.Labels = append(prInfo.Labels, label.GetName())
-		}
-
-		// Process assignee
-		if pr.Assignee != nil {
-			prInfo.Assignee = pr.Assignee.GetLogin()
-		}
-
-		// Process assignees
-		for _, assignee := range pr.Assignees {
-			prInfo.Assignees = append(prInfo.Assignees, assignee.GetLogin())
-		}
-
-		// Process requested reviewers
-		for _, reviewer := range pr.RequestedReviewers {
-			prInfo.RequestedReviewers = append(prInfo.RequestedReviewers, reviewer.GetLogin())
-		}
-
-		// Process creator
-		if pr.User != nil {
-			prInfo.Creator = pr.User.GetLogin()
-		}
-
-		// Process milestone
-		if pr.Milestone != nil {
-			prInfo.Milestone = &model.MilestoneInfo{
-				Number:      pr.Milestone.GetNumber(),
-				Title:       pr.Milestone.GetTitle(),
-				Description: pr.Milestone.GetDescription(),
-				State:       pr.Milestone.GetState(),
-			}
-			if pr.Milestone.DueOn != nil {
-				prInfo.Milestone.DueOn = pr.Milestone.DueOn.Format(time.RFC3339)
-			}
-		}
-
-		result.PRs = append(result.PRs, prInfo)
-	}
-
-	return result, nil
-}
-
-func (c *GithubClient) GetPullRequestByNumber(opt model.GetPullRequestByNumberOption) (*model.PRInfo, error) {
-	ctx := context.Background()
-
-	pr, _, err := c.c.PullRequests.Get(ctx, opt.Owner, opt.Repository, opt.Number)
-	if err != nil {
-		return nil, err
-	}
-
-	prInfo := &model.PRInfo{
+func pullRequestInfo(pr *github.PullRequest) model.PRInfo {
+	prInfo := model.PRInfo{
 		Number:         pr.GetNumber(),
 		Title:          pr.GetTitle(),
 		State:          pr.GetState(),
@@ -1207,18 +1642,70 @@ func (c *GithubClient) GetPullRequestByNumber(opt model.GetPullRequestByNumberOp
 		}
 	}
 
-	return prInfo, nil
+	return prInfo
 }
 
-func (c *GithubClient) SearchPullRequests(opt model.SearchPROption) (*model.PRListResult, error) {
+func (c *GithubClient) ListPullRequests(ctx context.Context, opt model.ListPROption) (*model.PRListResult, error) {
 	if opt.ResultPerpage == 0 {
-		opt.ResultPerpage = 10
+		opt.ResultPerpage = c.pageSize
+	}
+	if opt.Page == 0 {
+		opt.Page = 1
+	}
+	if opt.State == "" {
+		opt.State = "open"
+	}
+
+	opts := &github.PullRequestListOptions{
+		State:     opt.State,
+		Head:      opt.Head,
+		Base:      opt.Base,
+		Sort:      opt.Sort,
+		Direction: opt.Direction,
+		ListOptions: github.ListOptions{
+			PerPage: opt.ResultPerpage,
+			Page:    opt.Page,
+		},
+	}
+
+	prs, resp, err := c.c.PullRequests.List(ctx, opt.Owner, opt.Repository, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.PRListResult{
+		TotalCount: len(prs),
+		NextPage:   resp.NextPage,
+		LastPage:   resp.LastPage,
+		PRs:        make([]model.PRInfo, 0),
+	}
+
+	for _, pr := range prs {
+		result.PRs = append(result.PRs, pullRequestInfo(pr))
+	}
+
+	return result, nil
+}
+
+func (c *GithubClient) GetPullRequestByNumber(ctx context.Context, opt model.GetPullRequestByNumberOption) (*model.PRInfo, error) {
+	pr, _, err := c.c.PullRequests.Get(ctx, opt.Owner, opt.Repository, opt.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	prInfo := pullRequestInfo(pr)
+
+	return &prInfo, nil
+}
+
+func (c *GithubClient) SearchPullRequests(ctx context.Context, opt model.SearchPROption) (*model.PRListResult, error) {
+	if opt.ResultPerpage == 0 {
+		opt.ResultPerpage = c.pageSize
 	}
 	if opt.Page == 0 {
 		opt.Page = 1
 	}
 
-	ctx := context.Background()
 	opts := &github.SearchOptions{
 		Sort:  opt.Sort,
 		Order: opt.Order,
@@ -1242,10 +1729,12 @@ func (c *GithubClient) SearchPullRequests(opt model.SearchPROption) (*model.PRLi
 		PRs:        make([]model.PRInfo, 0),
 	}
 
+	var issues []*github.Issue
 	for _, issue := range result.Issues {
 		if !issue.IsPullRequest() {
 			continue // Should not happen with "is:pr" filter, but just in case
 		}
+		issues = append(issues, issue)
 
 		prInfo := model.PRInfo{
 			Number:    issue.GetNumber(),
@@ -1257,22 +1746,29 @@ func (c *GithubClient) SearchPullRequests(opt model.SearchPROption) (*model.PRLi
 			UpdatedAt: issue.GetUpdatedAt().Format(time.RFC3339),
 			URL:       issue.GetURL(),
 			HTMLURL:   issue.GetHTMLURL(),
-			Merged:    issue.GetState() == "closed" && strings.Contains(strings.ToLower(issue.GetTitle()), "merge"), // Heuristic for merged status
+			Draft:     issue.GetDraft(),
+			// search results carry the merge time of the pull request
+			Merged:   issue.GetPullRequestLinks().MergedAt != nil,
+			MergedAt: formatTimestamp(issue.GetPullRequestLinks().MergedAt),
 		}
 
 		if issue.ClosedAt != nil {
 			prInfo.ClosedAt = issue.ClosedAt.Format(time.RFC3339)
 		}
-		// GitHub Issue search results don't have MergedAt field, use ClosedAt as approximation
-		if issue.ClosedAt != nil && prInfo.Merged {
-			prInfo.MergedAt = issue.ClosedAt.Format(time.RFC3339)
-		}
 
 		// Process labels
 		for _, label := range issue.Labels {
 			prInfo.Labels = append(prInfo.Labels, label.GetName())
 		}
 
+		// Process assignees
+		if issue.Assignee != nil {
+			prInfo.Assignee = issue.Assignee.GetLogin()
+		}
+		for _, assignee := range issue.Assignees {
+			prInfo.Assignees = append(prInfo.Assignees, assignee.GetLogin())
+		}
+
 		// Process creator
 		if issue.User != nil {
 			prInfo.Creator = issue.User.GetLogin()
@@ -1281,28 +1777,145 @@ func (c *GithubClient) SearchPullRequests(opt model.SearchPROption) (*model.PRLi
 		searchResult.PRs = append(searchResult.PRs, prInfo)
 	}
 
+	if opt.Hydrate {
+		searchResult.HydrateErrors = c.hydratePullRequests(ctx, issues, searchResult.PRs)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
 	return searchResult, nil
 }
 
-func (c *GithubClient) CompareCommits(opt model.CompareCommitsOption) (*model.CompareCommitsResult, error) {
-	ctx := context.Background()
+// hydrateWorkers bounds the pull requests fetched at once by a hydrated search.
+const hydrateWorkers = 4
+
+// hydratePullRequests replaces the search results in prs with the full pull
+// requests, fetched concurrently. Pull requests failing to fetch keep their
+// search result and are returned as errors.
+func (c *GithubClient) hydratePullRequests(ctx context.Context, issues []*github.Issue, prs []model.PRInfo) []string {
+	errs := make([]string, len(issues))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(hydrateWorkers, len(issues)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				owner, repo, err := issueRepository(issues[i])
+				if err == nil {
+					var pr *github.PullRequest
+					pr, _, err = c.c.PullRequests.Get(ctx, owner, repo, issues[i].GetNumber())
+					if err == nil {
+						prs[i] = pullRequestInfo(pr)
+						continue
+					}
+				}
+				errs[i] = fmt.Sprintf("%s: %v", issues[i].GetHTMLURL(), err)
+			}
+		}()
+	}
+	for i := range issues {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
-	comparison, _, err := c.c.Repositories.CompareCommits(ctx, opt.Owner, opt.Repository, opt.Base, opt.Head, nil)
+	var failed []string
+	for _, err := range errs {
+		if err != "" {
+			failed = append(failed, err)
+		}
+	}
+	return failed
+}
+
+// issueRepository returns the owner and name of the repository of a search
+// result from its repository API URL.
+func issueRepository(issue *github.Issue) (owner, repo string, err error) {
+	parts := strings.Split(strings.TrimSuffix(issue.GetRepositoryURL(), "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-1] == "" || parts[len(parts)-2] == "" {
+		return "", "", fmt.Errorf("unknown repository URL %q", issue.GetRepositoryURL())
+	}
+	return parts[len(parts)-2], parts[len(parts)-1], nil
+}
+
+const (
+	// maxCompareFiles is the number of changed files GitHub lists for a comparison.
+	maxCompareFiles     = 300
+	defaultFilesPerPage = 100
+)
+
+// CompareCommits compares two refs with the commits and changed files paged
+// separately. Commits are always requested with paging, which lifts the limit
+// of 250 commits GitHub applies to unpaged comparisons.
+func (c *GithubClient) CompareCommits(ctx context.Context, opt model.CompareCommitsOption) (*model.CompareCommitsResult, error) {
+	if opt.ResultPerpage == 0 {
+		opt.ResultPerpage = c.pageSize
+	}
+	if opt.Page == 0 {
+		opt.Page = 1
+	}
+	// GitHub only lists the files with the first page of commits, later
+	// pages fetch them again only when files were asked for
+	wantFiles := opt.Page == 1 || opt.FilePage > 0 || opt.FilesPerPage > 0
+	if opt.FilesPerPage < 1 {
+		opt.FilesPerPage = defaultFilesPerPage
+	}
+	if opt.FilePage < 1 {
+		opt.FilePage = 1
+	}
+	listOpts := &github.ListOptions{
+		PerPage: opt.ResultPerpage,
+		Page:    opt.Page,
+	}
+	if opt.SummaryOnly {
+		listOpts = &github.ListOptions{PerPage: 1}
+	}
+
+	comparison, resp, err := c.c.Repositories.CompareCommits(ctx, opt.Owner, opt.Repository, opt.Base, opt.Head, listOpts)
 	if err != nil {
 		return nil, err
 	}
+	files := comparison.Files
+	if listOpts.Page > 1 && wantFiles {
+		first, _, err := c.c.Repositories.CompareCommits(ctx, opt.Owner, opt.Repository, opt.Base, opt.Head, &github.ListOptions{PerPage: 1, Page: 1})
+		if err != nil {
+			return nil, err
+		}
+		files = first.Files
+	}
 
+	// GitHub does not tell whether files were left out, a comparison of
+	// exactly 300 files is flagged as possibly truncated as well
 	result := &model.CompareCommitsResult{
-		TotalCommits: comparison.GetTotalCommits(),
-		AheadBy:      comparison.GetAheadBy(),
-		BehindBy:     comparison.GetBehindBy(),
-		HTMLURL:      comparison.GetHTMLURL(),
-		PermalinkURL: comparison.GetPermalinkURL(),
-		DiffURL:      comparison.GetDiffURL(),
-		PatchURL:     comparison.GetPatchURL(),
-		Status:       comparison.GetStatus(),
-		Commits:      make([]model.CommitInfo, 0),
-		Files:        make([]model.CommitFileInfo, 0),
+		TotalCommits:     comparison.GetTotalCommits(),
+		AheadBy:          comparison.GetAheadBy(),
+		BehindBy:         comparison.GetBehindBy(),
+		TotalFiles:       len(files),
+		FileLimitReached: len(files) >= maxCompareFiles,
+		HTMLURL:          comparison.GetHTMLURL(),
+		PermalinkURL:     comparison.GetPermalinkURL(),
+		DiffURL:          comparison.GetDiffURL(),
+		PatchURL:         comparison.GetPatchURL(),
+		Status:           comparison.GetStatus(),
+	}
+	if opt.SummaryOnly {
+		return result, nil
+	}
+
+	result.NextPage = resp.NextPage
+	result.LastPage = resp.LastPage
+	// the pages end before all commits were listed
+	perPage := min(opt.ResultPerpage, 100)
+	reachable := (opt.Page-1)*perPage + len(comparison.Commits)
+	if resp.LastPage != 0 {
+		reachable = resp.LastPage * perPage
+	}
+	result.CommitLimitReached = reachable < result.TotalCommits
+	result.Commits = make([]model.CommitInfo, 0)
+	if wantFiles {
+		result.Files = make([]model.CommitFileInfo, 0)
 	}
 
 	// Process commits
@@ -1334,22 +1947,14 @@ func (c *GithubClient) CompareCommits(opt model.CompareCommitsOption) (*model.Co
 		result.Commits = append(result.Commits, commitInfo)
 	}
 
-	// Process files
-	for _, file := range comparison.Files {
-		fileInfo := model.CommitFileInfo{
-			SHA:              file.GetSHA(),
-			Filename:         file.GetFilename(),
-			Additions:        file.GetAdditions(),
-			Deletions:        file.GetDeletions(),
-			Changes:          file.GetChanges(),
-			Status:           file.GetStatus(),
-			Patch:            file.GetPatch(),
-			BlobURL:          file.GetBlobURL(),
-			RawURL:           file.GetRawURL(),
-			ContentsURL:      file.GetContentsURL(),
-			PreviousFilename: file.GetPreviousFilename(),
-		}
-		result.Files = append(result.Files, fileInfo)
+	// Process files, GitHub returns them all at once so they are paged here
+	start := min((opt.FilePage-1)*opt.FilesPerPage, len(files))
+	end := min(start+opt.FilesPerPage, len(files))
+	if end < len(files) {
+		result.FileNextPage = opt.FilePage + 1
+	}
+	for _, file := range files[start:end] {
+		result.Files = append(result.Files, commitFileInfo(file))
 	}
 
 	return result, nil
@@ -1,9 +1,11 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/Felamande/githubMcp/model"
@@ -57,7 +59,7 @@ func TestCommitParentComparison(t *testing.T) {
 	client := &GithubClient{c: githubClient}
 
 	// Test GetCommitBySHA
-	commit, err := client.GetCommitBySHA(model.GetCommitBySHAOption{
+	commit, err := client.GetCommitBySHA(context.Background(), model.GetCommitBySHAOption{
 		Owner:      "pytorch",
 		Repository: "pytorch",
 		SHA:        "e2d141dbde55c2a4370fac5165b0561b6af4798b",
@@ -158,7 +160,7 @@ func TestListCommitsIncludesParents(t *testing.T) {
 	client := &GithubClient{c: githubClient}
 
 	// Test ListCommits
-	commits, err := client.ListCommits(model.CommitListOption{
+	commits, err := client.ListCommits(context.Background(), model.CommitListOption{
 		Owner:      "testowner",
 		Repository: "testrepo",
 		ResultPerpage: 10,
@@ -249,7 +251,7 @@ func TestGetCommitFilesBySHA(t *testing.T) {
 	client := &GithubClient{c: githubClient}
 
 	// Test GetCommitFilesBySHA
-	result, err := client.GetCommitFilesBySHA(model.GetCommitFilesBySHAOption{
+	result, err := client.GetCommitFilesBySHA(context.Background(), model.GetCommitFilesBySHAOption{
 		Owner:      "testowner",
 		Repository: "testrepo",
 		SHA:        "testsha1234567890abcdef1234567890abcdef12",
@@ -293,4 +295,195 @@ func TestGetCommitFilesBySHA(t *testing.T) {
 	if file2.Status != "added" {
 		t.Errorf("Expected status %s, got %s", "added", file2.Status)
 	}
-}
\ No newline at end of file
+}
+
+// TestListCommitsFilters tests that history filters are sent to GitHub and malformed timestamps are rejected
+func TestListCommitsFilters(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	githubClient := github.NewClient(nil)
+	githubClient.BaseURL, _ = githubClient.BaseURL.Parse(server.URL + "/")
+	client := &GithubClient{c: githubClient}
+
+	_, err := client.ListCommits(context.Background(), model.CommitListOption{
+		Owner:         "testowner",
+		Repository:    "testrepo",
+		SHA:           "release",
+		Path:          "src/main.go",
+		Author:        "alice",
+		Committer:     "web-flow",
+		Since:         "2024-01-02",
+		Until:         "2024-02-01T12:00:00+02:00",
+		ResultPerpage: 10,
+	})
+	if err != nil {
+		t.Fatalf("ListCommits failed: %v", err)
+	}
+	expected := map[string]string{
+		"sha":       "release",
+		"path":      "src/main.go",
+		"author":    "alice",
+		"committer": "web-flow",
+		"since":     "2024-01-02T00:00:00Z",
+		"until":     "2024-02-01T12:00:00+02:00",
+		"page":      "1",
+	}
+	for name, value := range expected {
+		if query.Get(name) != value {
+			t.Errorf("Expected %s=%s, got %q", name, value, query.Get(name))
+		}
+	}
+
+	// a date-only until covers the whole day
+	_, err = client.ListCommits(context.Background(), model.CommitListOption{
+		Owner:      "testowner",
+		Repository: "testrepo",
+		Since:      "2024-02-01",
+		Until:      "2024-02-01",
+	})
+	if err != nil {
+		t.Fatalf("ListCommits failed: %v", err)
+	}
+	if query.Get("since") != "2024-02-01T00:00:00Z" || query.Get("until") != "2024-02-01T23:59:59Z" {
+		t.Errorf("Expected the whole day of 2024-02-01, got since %q until %q", query.Get("since"), query.Get("until"))
+	}
+
+	invalid := []model.CommitListOption{
+		{Owner: "testowner", Repository: "testrepo", Since: "yesterday"},
+		{Owner: "testowner", Repository: "testrepo", Until: "2024-13-01"},
+		{Owner: "testowner", Repository: "testrepo", Since: "2024-03-01", Until: "2024-02-01"},
+	}
+	for _, opt := range invalid {
+		if _, err := client.ListCommits(context.Background(), opt); err == nil {
+			t.Errorf("Expected an error for since %q until %q", opt.Since, opt.Until)
+		}
+	}
+}
+
+// TestSearchCommits tests that commit search results carry their repository and pagination
+func TestSearchCommits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/commits" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		if q := r.URL.Query().Get("q"); q != "fix repo:testowner/testrepo" {
+			t.Errorf("Expected the query to be passed through, got %q", q)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Link", `<`+"http://"+r.Host+`/search/commits?page=2>; rel="next", <`+"http://"+r.Host+`/search/commits?page=5>; rel="last"`)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total_count": 42,
+			"items": []map[string]interface{}{
+				{
+					"sha":      "testsha",
+					"html_url": "https://github.com/testowner/testrepo/commit/testsha",
+					"commit": map[string]interface{}{
+						"message":   "fix the build",
+						"author":    map[string]interface{}{"name": "test author", "email": "author@example.com", "date": "2023-01-01T00:00:00Z"},
+						"committer": map[string]interface{}{"name": "test committer", "email": "committer@example.com"},
+					},
+					"parents":    []map[string]interface{}{{"sha": "parentsha"}},
+					"repository": map[string]interface{}{"name": "testrepo", "owner": map[string]interface{}{"login": "testowner"}},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	githubClient := github.NewClient(nil)
+	githubClient.BaseURL, _ = githubClient.BaseURL.Parse(server.URL + "/")
+	client := &GithubClient{c: githubClient, pageSize: 10}
+
+	result, err := client.SearchCommits(context.Background(), model.SearchCommitsOption{Query: "fix repo:testowner/testrepo"})
+	if err != nil {
+		t.Fatalf("SearchCommits failed: %v", err)
+	}
+	if result.TotalCount != 42 || result.NextPage != 2 || result.LastPage != 5 || len(result.Commits) != 1 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	commit := result.Commits[0]
+	if commit.SHA != "testsha" || commit.Owner != "testowner" || commit.Repository != "testrepo" || commit.Author != "test author" {
+		t.Errorf("Unexpected commit: %+v", commit)
+	}
+	if len(commit.ParentCommitHash) != 1 || commit.ParentCommitHash[0] != "parentsha" {
+		t.Errorf("Expected parent parentsha, got %v", commit.ParentCommitHash)
+	}
+}
+
+// TestCompareCommitsPaging tests that commits are paged by GitHub, files are paged locally and limits are reported
+func TestCompareCommitsPaging(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/testowner/testrepo/compare/v1...v2" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		queries = append(queries, r.URL.Query())
+		// like GitHub, files are only listed with the first page of commits
+		files := make([]map[string]interface{}, 0)
+		if page := r.URL.Query().Get("page"); page == "" || page == "1" {
+			for i := 0; i < 300; i++ {
+				files = append(files, map[string]interface{}{"filename": "file" + string(rune('a'+i%26)), "status": "modified"})
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Link", `<`+"http://"+r.Host+r.URL.Path+`?page=3>; rel="next", <`+"http://"+r.Host+r.URL.Path+`?page=40>; rel="last"`)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ahead", "ahead_by": 400, "behind_by": 0, "total_commits": 400,
+			"commits": []map[string]interface{}{{"sha": "c1"}, {"sha": "c2"}},
+			"files":   files,
+		})
+	}))
+	defer server.Close()
+
+	githubClient := github.NewClient(nil)
+	githubClient.BaseURL, _ = githubClient.BaseURL.Parse(server.URL + "/")
+	client := &GithubClient{c: githubClient, pageSize: 10}
+
+	result, err := client.CompareCommits(context.Background(), model.CompareCommitsOption{
+		Owner: "testowner", Repository: "testrepo", Base: "v1", Head: "v2", Page: 2, FilePage: 3,
+	})
+	if err != nil {
+		t.Fatalf("CompareCommits failed: %v", err)
+	}
+	if len(queries) != 2 || queries[0].Get("page") != "2" || queries[0].Get("per_page") != "10" || queries[1].Get("page") != "1" {
+		t.Fatalf("Expected the commit page and then the first page for the files, got %v", queries)
+	}
+	if result.NextPage != 3 || result.LastPage != 40 || len(result.Commits) != 2 || result.CommitLimitReached {
+		t.Errorf("Unexpected commit paging: next %d, last %d, %d commits, limit %v", result.NextPage, result.LastPage, len(result.Commits), result.CommitLimitReached)
+	}
+	if result.TotalFiles != 300 || len(result.Files) != 100 || result.FileNextPage != 0 || !result.FileLimitReached {
+		t.Errorf("Expected the last page of 300 files with the limit reached, got %d of %d files, next page %d", len(result.Files), result.TotalFiles, result.FileNextPage)
+	}
+
+	// later commit pages do not fetch the files again unless asked for
+	result, err = client.CompareCommits(context.Background(), model.CompareCommitsOption{
+		Owner: "testowner", Repository: "testrepo", Base: "v1", Head: "v2", Page: 2,
+	})
+	if err != nil {
+		t.Fatalf("CompareCommits failed: %v", err)
+	}
+	if len(queries) != 3 || result.Files != nil || result.TotalFiles != 0 || len(result.Commits) != 2 {
+		t.Errorf("Expected only the commit page without files, got %d requests and %+v", len(queries), result)
+	}
+
+	result, err = client.CompareCommits(context.Background(), model.CompareCommitsOption{
+		Owner: "testowner", Repository: "testrepo", Base: "v1", Head: "v2", SummaryOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("CompareCommits failed: %v", err)
+	}
+	if result.AheadBy != 400 || result.Status != "ahead" || result.Commits != nil || result.Files != nil || result.NextPage != 0 {
+		t.Errorf("Expected only the summary, got %+v", result)
+	}
+	if queries[3].Get("per_page") != "1" {
+		t.Errorf("Expected a summary to request a single commit, got %v", queries[3])
+	}
+}
@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Felamande/githubMcp/model"
+	"github.com/google/go-github/v74/github"
+)
+
+const defaultDiffMaxSize = 50000
+
+// GetDiff fetches the unified diff of a commit, a compare range or a pull
+// request and returns it parsed into files and hunks, starting at StartFile,
+// StartHunk and StartLine and stopping once MaxSize bytes of hunks were
+// returned.
+func (c *GithubClient) GetDiff(ctx context.Context, opt model.GetDiffOption) (*model.DiffResult, error) {
+	if opt.MaxSize == 0 {
+		opt.MaxSize = defaultDiffMaxSize
+	}
+	if opt.StartFile < 1 {
+		opt.StartFile = 1
+	}
+	if opt.StartHunk < 1 {
+		opt.StartHunk = 1
+	}
+	if opt.StartLine < 1 {
+		opt.StartLine = 1
+	}
+	if opt.MaxSize < 0 {
+		return nil, fmt.Errorf("max size must not be negative")
+	}
+	for _, glob := range []string{opt.Include, opt.Exclude} {
+		if _, err := matchGlob(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern: %v", err)
+		}
+	}
+
+	raw, err := c.rawDiff(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []model.DiffFile
+	for _, file := range parseDiff(raw) {
+		if includePath(file.Path, opt.Include, opt.Exclude) {
+			files = append(files, file)
+		}
+	}
+
+	result := &model.DiffResult{
+		TotalFiles: len(files),
+		Files:      make([]model.DiffFile, 0),
+	}
+	budget := opt.MaxSize
+	for i := opt.StartFile - 1; i < len(files) && result.NextFile == 0; i++ {
+		file := files[i]
+		file.Index = i + 1
+		hunks := file.Hunks
+		file.Hunks = make([]model.DiffHunk, 0)
+		first := 0
+		if i == opt.StartFile-1 {
+			first = min(opt.StartHunk-1, len(hunks))
+		}
+		for h := first; h < len(hunks); h++ {
+			hunk := hunks[h]
+			startLine := 1
+			if i == opt.StartFile-1 && h == first && opt.StartLine > 1 {
+				// continue a hunk cut by the previous call
+				startLine = opt.StartLine
+				hunk.Content = skipLines(hunk.Content, startLine-1)
+			}
+			if len(hunk.Content) > budget {
+				// once something was returned the hunk is left for the next
+				// call, a hunk larger than the whole budget is cut and
+				// continued by the next call from NextLine
+				if budget < opt.MaxSize {
+					result.NextFile, result.NextHunk = i+1, h+1
+					break
+				}
+				var lines int
+				hunk.Content, lines = cutLines(hunk.Content, budget)
+				hunk.Truncated = true
+				result.NextFile, result.NextHunk, result.NextLine = i+1, h+1, startLine+lines
+			}
+			budget -= len(hunk.Content)
+			file.Hunks = append(file.Hunks, hunk)
+			if result.NextFile != 0 {
+				break
+			}
+		}
+		if result.NextFile != 0 && len(file.Hunks) == 0 {
+			break
+		}
+		result.Files = append(result.Files, file)
+	}
+
+	return result, nil
+}
+
+// rawDiff fetches the diff of the commit, compare range or pull request
+// selected by opt.
+func (c *GithubClient) rawDiff(ctx context.Context, opt model.GetDiffOption) (string, error) {
+	diff := github.RawOptions{Type: github.Diff}
+	var raw string
+	var err error
+	switch {
+	case opt.SHA != "" && opt.Base == "" && opt.Head == "" && opt.Number == 0:
+		raw, _, err = c.c.Repositories.GetCommitRaw(ctx, opt.Owner, opt.Repository, opt.SHA, diff)
+	case opt.Base != "" && opt.Head != "" && opt.SHA == "" && opt.Number == 0:
+		raw, _, err = c.c.Repositories.CompareCommitsRaw(ctx, opt.Owner, opt.Repository, opt.Base, opt.Head, diff)
+	case opt.Number != 0 && opt.SHA == "" && opt.Base == "" && opt.Head == "":
+		raw, _, err = c.c.PullRequests.GetRaw(ctx, opt.Owner, opt.Repository, opt.Number, diff)
+	default:
+		return "", fmt.Errorf("exactly one of sha, base and head, or number must be given")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return raw, nil
+}
+
+// cutLines cuts s to the whole lines fitting in size bytes and returns how
+// many lines were kept. The first line is always kept, even when it alone is
+// longer, so that a cut hunk can be continued line by line.
+func cutLines(s string, size int) (string, int) {
+	end, lines := 0, 0
+	for end < len(s) {
+		next := len(s)
+		if i := strings.IndexByte(s[end:], '\n'); i >= 0 {
+			next = end + i + 1
+		}
+		if lines > 0 && len(strings.TrimSuffix(s[:next], "\n")) > size {
+			break
+		}
+		end = next
+		lines++
+	}
+	return strings.TrimSuffix(s[:end], "\n"), lines
+}
+
+// skipLines drops the first n lines of s.
+func skipLines(s string, n int) string {
+	for ; n > 0 && s != ""; n-- {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			return ""
+		}
+		s = s[i+1:]
+	}
+	return s
+}
+
+// parseDiff splits a unified git diff into files and hunks.
+func parseDiff(raw string) []model.DiffFile {
+	var files []model.DiffFile
+	var file *model.DiffFile
+	var hunk *model.DiffHunk
+	var content strings.Builder
+
+	endHunk := func() {
+		if hunk != nil {
+			hunk.Content = strings.TrimSuffix(content.String(), "\n")
+			file.Hunks = append(file.Hunks, *hunk)
+			hunk = nil
+		}
+		content.Reset()
+	}
+	endFile := func() {
+		endHunk()
+		if file != nil {
+			file.TotalHunks = len(file.Hunks)
+			files = append(files, *file)
+			file = nil
+		}
+	}
+
+	for _, line := range strings.SplitAfter(raw, "\n") {
+		text := strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(text, "diff --git "):
+			endFile()
+			file = &model.DiffFile{Status: "modified", Hunks: make([]model.DiffHunk, 0)}
+			oldPath, newPath := diffGitPaths(strings.TrimPrefix(text, "diff --git "))
+			file.OldPath, file.Path = oldPath, newPath
+		case file == nil:
+			// text before the first file, like the message of a patch
+		case strings.HasPrefix(text, "@@ "):
+			endHunk()
+			hunk = parseHunkHeader(text)
+			hunk.Index = len(file.Hunks) + 1
+			content.WriteString(line)
+		case hunk != nil:
+			content.WriteString(line)
+			if strings.HasPrefix(text, "+") {
+				file.Additions++
+			} else if strings.HasPrefix(text, "-") {
+				file.Deletions++
+			}
+		case strings.HasPrefix(text, "new file mode"):
+			file.Status = "added"
+		case strings.HasPrefix(text, "deleted file mode"):
+			file.Status = "removed"
+		case strings.HasPrefix(text, "rename from "):
+			file.Status = "renamed"
+			file.OldPath = strings.TrimPrefix(text, "rename from ")
+		case strings.HasPrefix(text, "rename to "):
+			file.Path = strings.TrimPrefix(text, "rename to ")
+		case strings.HasPrefix(text, "copy from "):
+			file.Status = "copied"
+			file.OldPath = strings.TrimPrefix(text, "copy from ")
+		case strings.HasPrefix(text, "copy to "):
+			file.Path = strings.TrimPrefix(text, "copy to ")
+		case strings.HasPrefix(text, "Binary files ") || text == "GIT binary patch":
+			file.Binary = true
+		case strings.HasPrefix(text, "--- "):
+			if path := strings.TrimPrefix(text, "--- "); path != "/dev/null" {
+				file.OldPath = strings.TrimPrefix(path, "a/")
+			}
+		case strings.HasPrefix(text, "+++ "):
+			if path := strings.TrimPrefix(text, "+++ "); path != "/dev/null" {
+				file.Path = strings.TrimPrefix(path, "b/")
+			}
+		}
+	}
+	endFile()
+
+	for i := range files {
+		if files[i].OldPath == files[i].Path {
+			files[i].OldPath = ""
+		}
+	}
+	return files
+}
+
+// diffGitPaths splits the "a/old b/new" paths of a diff --git line. Git
+// quotes paths with special characters, unquoted paths containing " b/" are
+// split in the middle when both are equal, as for binary or mode changes.
+// Renames are ambiguous here and fixed up by later header lines.
+func diffGitPaths(paths string) (oldPath, newPath string) {
+	if prefix, err := strconv.QuotedPrefix(paths); err == nil {
+		oldPath, _ = strconv.Unquote(prefix)
+		newPath = unquoteDiffPath(strings.TrimPrefix(paths[len(prefix):], " "))
+	} else if i := strings.LastIndex(paths, ` "`); i >= 0 && strings.HasSuffix(paths, `"`) {
+		oldPath = paths[:i]
+		newPath = unquoteDiffPath(paths[i+1:])
+	} else if n := (len(paths) - 1) / 2; len(paths) >= 5 && len(paths)%2 == 1 && strings.HasPrefix(paths, "a/") && paths[n:n+3] == " b/" && paths[2:n] == paths[n+3:] {
+		oldPath, newPath = paths[:n], paths[n+1:]
+	} else if before, after, ok := strings.Cut(paths, " b/"); ok {
+		oldPath, newPath = before, "b/"+after
+	} else {
+		return paths, paths
+	}
+	return strings.TrimPrefix(oldPath, "a/"), strings.TrimPrefix(newPath, "b/")
+}
+
+// unquoteDiffPath unquotes a path git quoted, other paths are returned as is.
+func unquoteDiffPath(path string) string {
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		return unquoted
+	}
+	return path
+}
+
+// parseHunkHeader parses a "@@ -1,5 +1,6 @@ section" hunk header.
+func parseHunkHeader(header string) *model.DiffHunk {
+	hunk := &model.DiffHunk{}
+	ranges, section, _ := strings.Cut(strings.TrimPrefix(header, "@@ "), " @@")
+	hunk.Section = strings.TrimSpace(section)
+	for _, r := range strings.Fields(ranges) {
+		start, lines := parseHunkRange(r[1:])
+		switch r[0] {
+		case '-':
+			hunk.OldStart, hunk.OldLines = start, lines
+		case '+':
+			hunk.NewStart, hunk.NewLines = start, lines
+		}
+	}
+	return hunk
+}
+
+// parseHunkRange parses "start,lines" where lines defaults to 1.
+func parseHunkRange(r string) (start, lines int) {
+	startText, linesText, ok := strings.Cut(r, ",")
+	start, _ = strconv.Atoi(startText)
+	lines = 1
+	if ok {
+		lines, _ = strconv.Atoi(linesText)
+	}
+	return start, lines
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+const testDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@ package main
+ import "fmt"
+-func a() {}
++func b() {}
+@@ -10,2 +10,3 @@ func main() {
+ 	fmt.Println()
++	b()
+ }
+diff --git a/old.txt b/new.txt
+similarity index 100%
+rename from old.txt
+rename to new.txt
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..3333333
+Binary files /dev/null and b/logo.png differ
+`
+
+// TestGetDiff tests that raw diffs are parsed into files and hunks and paged within the size budget
+func TestGetDiff(t *testing.T) {
+	var accepts []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/commits/abc", "/api/v3/repos/o/r/compare/main...dev", "/api/v3/repos/o/r/pulls/3":
+			accepts = append(accepts, r.Header.Get("Accept"))
+			w.Write([]byte(testDiff))
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	})
+
+	diff, err := client.GetDiff(context.Background(), model.GetDiffOption{Owner: "o", Repository: "r", SHA: "abc"})
+	if err != nil {
+		t.Fatalf("GetDiff failed: %v", err)
+	}
+	if diff.TotalFiles != 3 || len(diff.Files) != 3 || diff.NextFile != 0 {
+		t.Fatalf("Unexpected diff: %+v", diff)
+	}
+	file := diff.Files[0]
+	if file.Path != "main.go" || file.OldPath != "" || file.Status != "modified" || file.Additions != 2 || file.Deletions != 1 || file.TotalHunks != 2 {
+		t.Errorf("Unexpected file: %+v", file)
+	}
+	hunk := file.Hunks[1]
+	if hunk.Index != 2 || hunk.OldStart != 10 || hunk.OldLines != 2 || hunk.NewStart != 10 || hunk.NewLines != 3 || hunk.Section != "func main() {" || !strings.HasSuffix(hunk.Content, "+\tb()\n }") {
+		t.Errorf("Unexpected hunk: %+v", hunk)
+	}
+	if renamed := diff.Files[1]; renamed.Path != "new.txt" || renamed.OldPath != "old.txt" || renamed.Status != "renamed" {
+		t.Errorf("Unexpected rename: %+v", renamed)
+	}
+	if binary := diff.Files[2]; binary.Path != "logo.png" || binary.Status != "added" || !binary.Binary {
+		t.Errorf("Unexpected binary file: %+v", binary)
+	}
+
+	// the first hunk fits the budget, the second is left for the next call
+	diff, err = client.GetDiff(context.Background(), model.GetDiffOption{Owner: "o", Repository: "r", Base: "main", Head: "dev", MaxSize: 70})
+	if err != nil {
+		t.Fatalf("GetDiff failed: %v", err)
+	}
+	if len(diff.Files) != 1 || len(diff.Files[0].Hunks) != 1 || diff.NextFile != 1 || diff.NextHunk != 2 {
+		t.Fatalf("Expected to stop before the second hunk, got %+v", diff)
+	}
+
+	// a hunk larger than the budget is returned line by line from NextLine
+	var contents []string
+	opt := model.GetDiffOption{Owner: "o", Repository: "r", Number: 3, StartFile: 1, StartHunk: 2, MaxSize: 20}
+	for {
+		diff, err = client.GetDiff(context.Background(), opt)
+		if err != nil {
+			t.Fatalf("GetDiff failed: %v", err)
+		}
+		contents = append(contents, diff.Files[0].Hunks[0].Content)
+		if diff.NextFile == 0 {
+			break
+		}
+		if diff.NextFile != 1 || diff.NextHunk != 2 || diff.NextLine <= opt.StartLine || !diff.Files[0].Hunks[0].Truncated {
+			t.Fatalf("Expected the oversized hunk to continue on a later line, got %+v", diff)
+		}
+		opt.StartLine = diff.NextLine
+	}
+	if strings.Join(contents, "\n") != "@@ -10,2 +10,3 @@ func main() {\n \tfmt.Println()\n+\tb()\n }" || len(contents) != 3 {
+		t.Errorf("Expected the hunk to be returned whole over three calls, got %q", contents)
+	}
+	if len(diff.Files) != 3 || diff.Files[0].Hunks[0].Truncated {
+		t.Errorf("Expected the files without hunks to follow the last part of the hunk, got %+v", diff)
+	}
+
+	diff, err = client.GetDiff(context.Background(), model.GetDiffOption{Owner: "o", Repository: "r", SHA: "abc", Include: "*.png"})
+	if err != nil {
+		t.Fatalf("GetDiff failed: %v", err)
+	}
+	if diff.TotalFiles != 1 || diff.Files[0].Index != 1 || diff.Files[0].Path != "logo.png" {
+		t.Errorf("Expected only the png file, got %+v", diff)
+	}
+
+	for _, accept := range accepts {
+		if accept != "application/vnd.github.v3.diff" {
+			t.Errorf("Expected a diff to be requested, got %s", accept)
+		}
+	}
+	if _, err := client.GetDiff(context.Background(), model.GetDiffOption{Owner: "o", Repository: "r", SHA: "abc", Number: 3}); err == nil {
+		t.Errorf("Expected an error when both a commit and a pull request are given")
+	}
+}
+
+func TestDiffGitPaths(t *testing.T) {
+	cases := []struct {
+		paths    string
+		old, new string
+	}{
+		{"a/main.go b/main.go", "main.go", "main.go"},
+		{"a/docs b/x.png b/docs b/x.png", "docs b/x.png", "docs b/x.png"},
+		{`"a/tab\there.txt" "b/tab\there.txt"`, "tab\there.txt", "tab\there.txt"},
+		{`a/plain.txt "b/caf\303\251.txt"`, "plain.txt", "caf\u00e9.txt"},
+		{"a/old.txt b/new.txt", "old.txt", "new.txt"},
+	}
+	for _, c := range cases {
+		if old, new := diffGitPaths(c.paths); old != c.old || new != c.new {
+			t.Errorf("diffGitPaths(%q) = %q, %q, want %q, %q", c.paths, old, new, c.old, c.new)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// maxDownloadSize bounds the size of a downloaded archive.
+	maxDownloadSize = 1 << 30
+	// defaultDownloadMaxAge is how long a download is kept without being used.
+	defaultDownloadMaxAge = 7 * 24 * time.Hour
+	// downloadPruneInterval bounds how often the download directory is pruned.
+	downloadPruneInterval = time.Hour
+)
+
+// downloadPath returns where the file identified by key is kept below the
+// download directory. Keys are hashed so that user supplied names never
+// reach the file system.
+func (c *GithubClient) downloadPath(kind, key, ext string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.downloadDir, kind, hex.EncodeToString(sum[:])+ext)
+}
+
+// downloadOnce fetches url to dest unless dest already exists. Concurrent
+// calls for the same dest wait for the first download instead of repeating it.
+func (c *GithubClient) downloadOnce(ctx context.Context, url, dest string) error {
+	unlock := c.lockDownload(dest)
+	defer unlock()
+
+	if c.downloaded(dest) {
+		return nil
+	}
+
+	resp, err := c.startDownload(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = writeFileAtomic(dest, func(w io.Writer) error {
+		n, err := io.Copy(w, io.LimitReader(resp.Body, maxDownloadSize+1))
+		if err == nil && n > maxDownloadSize {
+			err = fmt.Errorf("download exceeds %d bytes", maxDownloadSize)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	c.startPrune()
+	return nil
+}
+
+// writeFileAtomic creates dest with the content written by write. The content
+// goes to a temporary file renamed into place once complete, so readers never
+// see a partial file.
+func writeFileAtomic(dest string, write func(w io.Writer) error) error {
+	dir := filepath.Dir(dest)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	err = write(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), dest)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// downloaded reports whether dest was downloaded before and marks it as
+// used, so that pruneDownloads keeps it.
+func (c *GithubClient) downloaded(dest string) bool {
+	if _, err := os.Stat(dest); err != nil {
+		return false
+	}
+	now := time.Now()
+	os.Chtimes(dest, now, now)
+	return true
+}
+
+// startPrune prunes the download directory in the background, at most once
+// every downloadPruneInterval.
+func (c *GithubClient) startPrune() {
+	if c.downloadMaxAge == 0 {
+		return
+	}
+	c.pruneMu.Lock()
+	defer c.pruneMu.Unlock()
+	if !c.lastPrune.IsZero() && time.Since(c.lastPrune) < downloadPruneInterval {
+		return
+	}
+	c.lastPrune = time.Now()
+	go c.pruneDownloads()
+}
+
+// pruneDownloads removes the files of the download directory not used within
+// the max age.
+func (c *GithubClient) pruneDownloads() {
+	cutoff := time.Now().Add(-c.downloadMaxAge)
+	filepath.WalkDir(c.downloadDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().Before(cutoff) {
+			os.Remove(path)
+		}
+		return nil
+	})
+}
+
+// downloadBytes fetches url into memory, failing when it exceeds limit bytes.
+func (c *GithubClient) downloadBytes(ctx context.Context, url string, limit int64) ([]byte, error) {
+	resp, err := c.startDownload(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("download exceeds %d bytes", limit)
+	}
+	return data, nil
+}
+
+func (c *GithubClient) startDownload(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// download URLs are signed, the token is not sent along to storage hosts
+	resp, err := c.download.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download failed: unexpected status %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// downloadLock serializes the downloads of one destination, refs counts the
+// calls holding or waiting for it.
+type downloadLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lockDownload locks dest and returns the function unlocking it. The lock is
+// dropped once no call uses it, so finished downloads do not keep one.
+func (c *GithubClient) lockDownload(dest string) (unlock func()) {
+	c.downloadsMu.Lock()
+	if c.downloads == nil {
+		c.downloads = make(map[string]*downloadLock)
+	}
+	lock, ok := c.downloads[dest]
+	if !ok {
+		lock = &downloadLock{}
+		c.downloads[dest] = lock
+	}
+	lock.refs++
+	c.downloadsMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		c.downloadsMu.Lock()
+		defer c.downloadsMu.Unlock()
+		if lock.refs--; lock.refs == 0 {
+			delete(c.downloads, dest)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+// TestEnterpriseURLs tests that requests go to the /api/v3/ prefix of a GitHub Enterprise Server
+func TestEnterpriseURLs(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if got := r.Header.Get("Authorization"); got != "Bearer enterprise-token" {
+			t.Errorf("Expected enterprise token to be sent, got %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/repos/octo/hello/readme":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"type":     "file",
+				"encoding": "base64",
+				"content":  base64.StdEncoding.EncodeToString([]byte("# hello\nenterprise\n")),
+			})
+		case "/api/v3/search/code":
+			if q := r.URL.Query().Get("q"); q != "func main" {
+				t.Errorf("Expected query %q, got %q", "func main", q)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"total_count": 1,
+				"items": []map[string]interface{}{
+					{
+						"name": "main.go",
+						"path": "cmd/main.go",
+						"repository": map[string]interface{}{
+							"name":  "hello",
+							"owner": map[string]interface{}{"login": "octo"},
+						},
+					},
+				},
+			})
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("enterprise-token", WithEnterpriseURLs(server.URL, ""))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	readme, err := client.GetReadme(context.Background(), model.ReadmeOption{Owner: "octo", Repository: "hello", EndLine: 1})
+	if err != nil {
+		t.Fatalf("GetReadme failed: %v", err)
+	}
+	if readme.Content != "# hello" || readme.TotalLines != 3 {
+		t.Errorf("Unexpected readme result: %+v", readme)
+	}
+
+	code, err := client.SearchCode(context.Background(), model.SearchCodeOption{Query: "func main"})
+	if err != nil {
+		t.Fatalf("SearchCode failed: %v", err)
+	}
+	if len(code.CodeFiles) != 1 || code.CodeFiles[0].Path != "cmd/main.go" || code.CodeFiles[0].Owner != "octo" {
+		t.Errorf("Unexpected search result: %+v", code)
+	}
+
+	if len(paths) != 2 {
+		t.Errorf("Expected 2 requests to the enterprise server, got %v", paths)
+	}
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+func newFileTestClient(t *testing.T, files map[string][]byte) *GithubClient {
+	t.Helper()
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if sha, ok := strings.CutPrefix(r.URL.Path, "/api/v3/repos/o/r/git/blobs/"); ok {
+			if r.Header.Get("Accept") != "application/vnd.github.v3.raw" {
+				t.Errorf("Expected a raw blob request, got Accept %q", r.Header.Get("Accept"))
+			}
+			w.Write(files[sha])
+			return
+		}
+		name, ok := strings.CutPrefix(r.URL.Path, "/api/v3/repos/o/r/contents/")
+		data, found := files[name]
+		if !ok || !found {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		response := map[string]interface{}{"type": "file", "sha": name, "size": len(data)}
+		// the contents API leaves out files over 1 MB
+		if len(data) > 1<<20 {
+			response["encoding"] = "none"
+			response["content"] = ""
+		} else {
+			response["encoding"] = "base64"
+			response["content"] = base64.StdEncoding.EncodeToString(data)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+}
+
+// TestReadLargeFile tests that files over 1 MB are read through the Git Blobs API
+func TestReadLargeFile(t *testing.T) {
+	large := strings.Repeat(strings.Repeat("x", 99)+"\n", 20000) + "last line"
+	client := newFileTestClient(t, map[string][]byte{"large.txt": []byte(large)})
+
+	file, err := client.ReadFile(context.Background(), model.ReadFileOption{Owner: "o", Repository: "r", Path: "large.txt", StartLine: 20001})
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if file.Content != "last line" || file.TotalLines != 20001 || file.Size != len(large) {
+		t.Errorf("Unexpected result: content %q, %d lines, %d bytes", file.Content, file.TotalLines, file.Size)
+	}
+
+	file, err = client.ReadFile(context.Background(), model.ReadFileOption{Owner: "o", Repository: "r", Path: "large.txt", StartLine: 20002})
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if file.Content != "" || file.StartLine != 0 || file.EndLine != 0 || file.TotalLines != 20001 {
+		t.Errorf("Expected an empty range past the end of the file, got %q (%d-%d of %d)", file.Content, file.StartLine, file.EndLine, file.TotalLines)
+	}
+
+	file, err = client.ReadFile(context.Background(), model.ReadFileOption{Owner: "o", Repository: "r", Path: "large.txt", StartByte: 98, EndByte: 101})
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if file.Content != "x\nx" || file.StartByte != 98 || file.EndByte != 101 {
+		t.Errorf("Unexpected byte range: %q (%d-%d)", file.Content, file.StartByte, file.EndByte)
+	}
+}
+
+// TestReadFileByteRangeRunes tests that byte ranges of text files never split a UTF-8 sequence
+func TestReadFileByteRangeRunes(t *testing.T) {
+	text := "h\u00e9llo \u4e16\u754c"
+	client := newFileTestClient(t, map[string][]byte{"hello.txt": []byte(text)})
+
+	// byte 2 is inside \u00e9, byte 9 inside \u4e16
+	file, err := client.ReadFile(context.Background(), model.ReadFileOption{Owner: "o", Repository: "r", Path: "hello.txt", StartByte: 2, EndByte: 9})
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if file.Binary || file.Content != "\u00e9llo \u4e16" || file.StartByte != 1 || file.EndByte != 10 {
+		t.Errorf("Expected the range widened to whole runes, got %q (%d-%d)", file.Content, file.StartByte, file.EndByte)
+	}
+}
+
+// TestReadBinaryFile tests that binary files are described instead of split into lines
+func TestReadBinaryFile(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	client := newFileTestClient(t, map[string][]byte{"logo.png": png})
+
+	file, err := client.ReadFile(context.Background(), model.ReadFileOption{Owner: "o", Repository: "r", Path: "logo.png"})
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	sum := sha256.Sum256(png)
+	if !file.Binary || file.Content != "" || file.MIMEType != "image/png" || file.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected binary result: %+v", file)
+	}
+
+	file, err = client.ReadFile(context.Background(), model.ReadFileOption{Owner: "o", Repository: "r", Path: "logo.png", EndByte: 4})
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if file.Encoding != "base64" || file.Content != base64.StdEncoding.EncodeToString(png[:4]) {
+		t.Errorf("Expected the base64 encoded range, got %q (%s)", file.Content, file.Encoding)
+	}
+
+	if _, err := client.ReadFile(context.Background(), model.ReadFileOption{Owner: "o", Repository: "r", Path: "logo.png", StartByte: 4, EndByte: 2}); err == nil {
+		t.Errorf("Expected an error for an empty byte range")
+	}
+}
+
+// TestReadFiles tests that files are read concurrently with per-file errors and an output budget
+func TestReadFiles(t *testing.T) {
+	client := newFileTestClient(t, map[string][]byte{
+		"a.txt": []byte("one\ntwo\nthree"),
+		"b.txt": []byte("0123456789"),
+		"c.txt": []byte("abc"),
+	})
+
+	result, err := client.ReadFiles(context.Background(), model.ReadFilesOption{
+		Owner:      "o",
+		Repository: "r",
+		Files: []model.FileLineRange{
+			{Path: "a.txt", StartLine: 2, EndLine: 2},
+			{Path: "missing.txt"},
+			{Path: "b.txt"},
+			{Path: "c.txt"},
+		},
+		MaxOutputSize: 8,
+	})
+	if err != nil {
+		t.Fatalf("ReadFiles failed: %v", err)
+	}
+	if len(result.Files) != 4 || !result.BudgetExceeded {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if a := result.Files[0]; a.Path != "a.txt" || a.File == nil || a.File.Content != "two" {
+		t.Errorf("Unexpected first file: %+v", a)
+	}
+	if missing := result.Files[1]; missing.Error == "" || missing.File != nil {
+		t.Errorf("Expected an error for the missing file, got %+v", missing)
+	}
+	if b := result.Files[2]; !b.OverBudget || b.File.Content != "" || b.File.TotalLines != 1 {
+		t.Errorf("Expected b.txt to be over budget, got %+v", b)
+	}
+	if c := result.Files[3]; c.OverBudget || c.File.Content != "abc" {
+		t.Errorf("Expected c.txt to fit the remaining budget, got %+v", c)
+	}
+}
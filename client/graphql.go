@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type graphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphqlURL returns the GraphQL endpoint next to the REST API, which is
+// api.github.com/graphql on github.com and /api/graphql on Enterprise Server.
+func (c *GithubClient) graphqlURL() string {
+	base := c.c.BaseURL.String()
+	if prefix, ok := strings.CutSuffix(base, "/api/v3/"); ok {
+		return prefix + "/api/graphql"
+	}
+	return base + "graphql"
+}
+
+// graphql runs query and decodes its data into out. GraphQL reports most
+// failures with a 200 response, those errors are returned as well.
+func (c *GithubClient) graphql(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	req, err := c.c.NewRequest("POST", c.graphqlURL(), &graphqlRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+	resp := &graphqlResponse{}
+	if _, err := c.c.Do(ctx, req, resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("graphql: %s", strings.Join(messages, "; "))
+	}
+	return json.Unmarshal(resp.Data, out)
+}
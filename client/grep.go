@@ -0,0 +1,186 @@
+package client
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Felamande/githubMcp/model"
+	"github.com/google/go-github/v74/github"
+)
+
+const (
+	defaultGrepMaxMatches = 100
+	// maxGrepFileSize skips files too large to be source code.
+	maxGrepFileSize = 10 << 20
+)
+
+// repositoryArchive returns the commit ref resolves to and the path of its
+// tarball, downloading it on first use. Archives are keyed by commit so a
+// moved branch is downloaded again while a pinned commit never is.
+func (c *GithubClient) repositoryArchive(ctx context.Context, owner, repo, ref string) (string, string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	sha, _, err := c.c.Repositories.GetCommitSHA1(ctx, owner, repo, ref, "")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve ref %s: %w", ref, err)
+	}
+
+	dest := c.downloadPath("archives", owner+"/"+repo+"@"+sha, ".tar.gz")
+	if c.downloaded(dest) {
+		return sha, dest, nil
+	}
+	link, _, err := c.c.Repositories.GetArchiveLink(ctx, owner, repo, github.Tarball, &github.RepositoryContentGetOptions{Ref: sha}, 0)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get archive link: %w", err)
+	}
+	if err := c.downloadOnce(ctx, link.String(), dest); err != nil {
+		return "", "", err
+	}
+	return sha, dest, nil
+}
+
+// GrepRepository searches the files of a ref with a regular expression. The
+// tarball of the ref is downloaded once and searched locally, so unlike code
+// search it covers every branch, fork and file size.
+func (c *GithubClient) GrepRepository(ctx context.Context, opt model.GrepRepositoryOption) (*model.GrepRepositoryResult, error) {
+	if opt.MaxMatches == 0 {
+		opt.MaxMatches = defaultGrepMaxMatches
+	}
+	if opt.MaxMatches < 0 {
+		return nil, fmt.Errorf("max matches must not be negative, got %d", opt.MaxMatches)
+	}
+	if opt.ContextLines < 0 {
+		return nil, fmt.Errorf("context lines must not be negative, got %d", opt.ContextLines)
+	}
+	expr := opt.Pattern
+	if opt.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %v", err)
+	}
+	for _, glob := range []string{opt.Include, opt.Exclude} {
+		if _, err := matchGlob(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern: %v", err)
+		}
+	}
+
+	sha, archive, err := c.repositoryArchive(ctx, opt.Owner, opt.Repository, opt.Ref)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %v", err)
+	}
+	defer gz.Close()
+
+	result := &model.GrepRepositoryResult{
+		CommitSHA: sha,
+		Files:     make([]model.GrepFileInfo, 0),
+	}
+	tr := tar.NewReader(gz)
+	for !result.LimitReached {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg || header.Size > maxGrepFileSize {
+			continue
+		}
+		// entries are prefixed with a <owner>-<repo>-<sha> directory
+		_, name, ok := strings.Cut(header.Name, "/")
+		if !ok || !includePath(name, opt.Include, opt.Exclude) {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive: %v", err)
+		}
+		if isBinary(data) {
+			continue
+		}
+		result.FilesSearched++
+
+		matches := grepLines(pattern, strings.Split(string(data), "\n"), opt.ContextLines, opt.MaxMatches-result.TotalMatches)
+		if len(matches) == 0 {
+			continue
+		}
+		result.TotalMatches += len(matches)
+		result.LimitReached = result.TotalMatches >= opt.MaxMatches
+		result.Files = append(result.Files, model.GrepFileInfo{Path: name, TextMatches: matches})
+	}
+
+	return result, nil
+}
+
+func includePath(name, include, exclude string) bool {
+	if include != "" {
+		if ok, _ := matchGlob(include, name); !ok {
+			return false
+		}
+	}
+	if exclude != "" {
+		if ok, _ := matchGlob(exclude, name); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// grepLines returns up to limit matching lines, each with contextLines lines
+// around it.
+func grepLines(pattern *regexp.Regexp, lines []string, contextLines, limit int) []model.GrepMatch {
+	var matches []model.GrepMatch
+	for i, line := range lines {
+		if len(matches) >= limit {
+			break
+		}
+		indices := pattern.FindAllStringIndex(line, -1)
+		if len(indices) == 0 {
+			continue
+		}
+
+		start := max(i-contextLines, 0)
+		end := min(i+contextLines+1, len(lines))
+		// offset of the matching line within the fragment
+		offset := 0
+		for _, before := range lines[start:i] {
+			offset += len(before) + 1
+		}
+		match := model.GrepMatch{
+			Line:      i + 1,
+			StartLine: start + 1,
+			EndLine:   end,
+			Fragment:  strings.Join(lines[start:end], "\n"),
+		}
+		for _, index := range indices {
+			match.Matches = append(match.Matches, model.MatchDetail{
+				Indices: []int{offset + index[0], offset + index[1]},
+				Text:    line[index[0]:index[1]],
+			})
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+func newTarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "o-r-abc123/", Typeflag: tar.TypeDir, Mode: 0o755})
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: "o-r-abc123/" + name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))})
+		tw.Write([]byte(content))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar failed: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestGrepRepository tests that the tarball is downloaded once and searched with globs and context
+func TestGrepRepository(t *testing.T) {
+	tarball := newTarball(t, map[string]string{
+		"main.go":         "package main\n\nfunc main() {\n\tTODO()\n}\n",
+		"pkg/util.go":     "package pkg\n// todo: fix\nfunc TODO() {}\n",
+		"docs/notes.md":   "TODO write docs\n",
+		"assets/logo.bin": "TODO\x00\x01",
+	})
+	downloads := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/commits/main":
+			w.Write([]byte("abc123"))
+		case "/api/v3/repos/o/r/tarball/abc123":
+			http.Redirect(w, r, server.URL+"/codeload/o/r/abc123.tar.gz", http.StatusFound)
+		case "/codeload/o/r/abc123.tar.gz":
+			if r.Header.Get("Authorization") != "" {
+				t.Errorf("Expected the token to stay with the API, got %q", r.Header.Get("Authorization"))
+			}
+			downloads++
+			w.Write(tarball)
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("secret", WithEnterpriseURLs(server.URL, ""), WithDownloadDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	result, err := client.GrepRepository(context.Background(), model.GrepRepositoryOption{
+		Owner: "o", Repository: "r", Ref: "main", Pattern: "todo", IgnoreCase: true, Include: "**/*.go", ContextLines: 1,
+	})
+	if err != nil {
+		t.Fatalf("GrepRepository failed: %v", err)
+	}
+	if result.CommitSHA != "abc123" || result.FilesSearched != 2 || result.TotalMatches != 3 || result.LimitReached {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	for _, file := range result.Files {
+		if file.Path != "main.go" {
+			continue
+		}
+		match := file.TextMatches[0]
+		if match.Line != 4 || match.StartLine != 3 || match.EndLine != 5 || match.Fragment != "func main() {\n\tTODO()\n}" {
+			t.Errorf("Unexpected match: %+v", match)
+		}
+		if detail := match.Matches[0]; match.Fragment[detail.Indices[0]:detail.Indices[1]] != "TODO" {
+			t.Errorf("Expected indices of TODO in the fragment, got %v", detail.Indices)
+		}
+	}
+
+	result, err = client.GrepRepository(context.Background(), model.GrepRepositoryOption{
+		Owner: "o", Repository: "r", Ref: "main", Pattern: "TODO", Exclude: "*.md", MaxMatches: 1,
+	})
+	if err != nil {
+		t.Fatalf("GrepRepository failed: %v", err)
+	}
+	if result.TotalMatches != 1 || !result.LimitReached {
+		t.Errorf("Expected the match limit to be reached, got %+v", result)
+	}
+	if downloads != 1 {
+		t.Errorf("Expected the archive to be downloaded once, got %d", downloads)
+	}
+	if len(client.downloads) != 0 {
+		t.Errorf("Expected the download locks to be dropped, got %d", len(client.downloads))
+	}
+
+	if _, err := client.GrepRepository(context.Background(), model.GrepRepositoryOption{
+		Owner: "o", Repository: "r", Ref: "main", Pattern: "TODO", MaxMatches: -1,
+	}); err == nil {
+		t.Errorf("Expected an error for negative max matches")
+	}
+}
+
+// TestPruneDownloads tests that downloads not used within the max age are removed
+func TestPruneDownloads(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "archives", "old.tar.gz")
+	recent := filepath.Join(dir, "archives", "recent.tar.gz")
+	os.MkdirAll(filepath.Dir(old), 0o700)
+	os.WriteFile(old, []byte("old"), 0o600)
+	os.WriteFile(recent, []byte("recent"), 0o600)
+	stale := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(old, stale, stale)
+
+	client, err := NewClient("", WithDownloadDir(dir), WithDownloadMaxAge(time.Hour))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	// the client started a background prune, a second one is rate limited
+	lastPrune := client.lastPrune
+	client.startPrune()
+	if lastPrune.IsZero() || client.lastPrune != lastPrune {
+		t.Errorf("Expected one prune on startup, last prune %v then %v", lastPrune, client.lastPrune)
+	}
+
+	client.pruneDownloads()
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("Expected the stale download to be removed, got %v", err)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("Expected the recent download to be kept, got %v", err)
+	}
+
+	if unset, err := NewClient(""); err != nil || !unset.lastPrune.IsZero() {
+		t.Errorf("Expected the default download dir not to be pruned on startup, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient returns a client talking to a test server serving handler as
+// a GitHub Enterprise Server, so API paths start with /api/v3/.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *GithubClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := NewClient("", append([]Option{WithEnterpriseURLs(server.URL, "")}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	return client
+}
+
+// writeEndlessPage answers with page and a Link header to a next page, so that
+// paging only stops at the page limit of the client.
+func writeEndlessPage(w http.ResponseWriter, r *http.Request, page interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Link", `<http://`+r.Host+r.URL.Path+`?page=99>; rel="next"`)
+	json.NewEncoder(w).Encode(page)
+}
@@ -0,0 +1,230 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+	"github.com/google/go-github/v74/github"
+)
+
+const (
+	defaultLogTailLines    = 30
+	defaultLogContextLines = 5
+	// maxLogSize bounds the size of a job log read into memory.
+	maxLogSize = 64 << 20
+)
+
+// logLine is a line of a job log split into its timestamp and text.
+type logLine struct {
+	time time.Time
+	raw  string
+	text string
+}
+
+// logRange is a 0-based inclusive range of log lines picked in failures mode.
+type logRange struct {
+	start, end int
+	reason     string
+	step       string
+}
+
+// GetJobLogs returns the log of a workflow job, either as a window of lines or
+// reduced to the sections explaining why the job failed.
+func (c *GithubClient) GetJobLogs(ctx context.Context, opt model.GetJobLogsOption) (*model.JobLogsResult, error) {
+	// unset counts take the default, an explicit 0 is kept
+	tailLines, contextLines := defaultLogTailLines, defaultLogContextLines
+	if opt.TailLines != nil {
+		tailLines = *opt.TailLines
+	}
+	if opt.ContextLines != nil {
+		contextLines = *opt.ContextLines
+	}
+	if tailLines < 0 || contextLines < 0 {
+		return nil, fmt.Errorf("tail lines and context lines must not be negative")
+	}
+
+	job, _, err := c.c.Actions.GetWorkflowJobByID(ctx, opt.Owner, opt.Repository, opt.JobID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.jobLog(ctx, opt, job)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := parseLogLines(string(data))
+	result := &model.JobLogsResult{
+		JobID:      job.GetID(),
+		JobName:    job.GetName(),
+		Status:     job.GetStatus(),
+		Conclusion: job.GetConclusion(),
+		TotalLines: len(lines),
+	}
+
+	if opt.FailuresOnly {
+		result.Sections = make([]model.LogSection, 0)
+		for _, r := range failureRanges(job, lines, tailLines, contextLines) {
+			result.Sections = append(result.Sections, model.LogSection{
+				Reason:    r.reason,
+				Step:      r.step,
+				StartLine: r.start + 1,
+				EndLine:   r.end + 1,
+				Content:   joinLogLines(lines[r.start:r.end+1], opt.Timestamps),
+			})
+		}
+		return result, nil
+	}
+
+	if opt.StartLine < 1 {
+		opt.StartLine = 1
+	}
+	if opt.EndLine == 0 || opt.EndLine > len(lines) {
+		opt.EndLine = len(lines)
+	}
+	if opt.StartLine > opt.EndLine {
+		opt.StartLine = opt.EndLine + 1
+	}
+	result.StartLine = opt.StartLine
+	result.EndLine = opt.EndLine
+	result.Content = joinLogLines(lines[opt.StartLine-1:opt.EndLine], opt.Timestamps)
+
+	return result, nil
+}
+
+// jobLog downloads the log of a job. Logs of completed jobs no longer change
+// and are kept in the download directory.
+func (c *GithubClient) jobLog(ctx context.Context, opt model.GetJobLogsOption, job *github.WorkflowJob) ([]byte, error) {
+	link, _, err := c.c.Actions.GetWorkflowJobLogs(ctx, opt.Owner, opt.Repository, opt.JobID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log link: %w", err)
+	}
+	if job.GetStatus() != "completed" {
+		return c.downloadBytes(ctx, link.String(), maxLogSize)
+	}
+
+	dest := c.downloadPath("logs", opt.Owner+"/"+opt.Repository+"#"+strconv.FormatInt(opt.JobID, 10), ".log")
+	if err := c.downloadOnce(ctx, link.String(), dest); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(dest); err == nil && info.Size() > maxLogSize {
+		return nil, fmt.Errorf("job log exceeds %d bytes", maxLogSize)
+	}
+	return os.ReadFile(dest)
+}
+
+// parseLogLines splits a job log into lines, separating the RFC 3339
+// timestamp GitHub prefixes every line with.
+func parseLogLines(data string) []logLine {
+	data = strings.TrimPrefix(data, "\ufeff")
+	data = strings.TrimSuffix(data, "\n")
+	if data == "" {
+		return nil
+	}
+	raw := strings.Split(data, "\n")
+	lines := make([]logLine, len(raw))
+	for i, line := range raw {
+		line = strings.TrimSuffix(line, "\r")
+		lines[i] = logLine{raw: line, text: line}
+		prefix, text, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, prefix); err == nil {
+			lines[i].time = t
+			lines[i].text = text
+		}
+	}
+	return lines
+}
+
+func joinLogLines(lines []logLine, timestamps bool) string {
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		if timestamps {
+			texts[i] = line.raw
+		} else {
+			texts[i] = line.text
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// stepContains reports whether t falls within a step. Step times only have
+// second precision, so the second the step completed in is included.
+func stepContains(step *github.TaskStep, t time.Time) bool {
+	if t.IsZero() || step.StartedAt == nil || step.CompletedAt == nil {
+		return false
+	}
+	return !t.Before(step.StartedAt.Time) && t.Before(step.CompletedAt.Add(time.Second))
+}
+
+// failureRanges picks the lines around ##[error] annotations and the last
+// tailLines lines of every failed step, merging ranges that overlap.
+func failureRanges(job *github.WorkflowJob, lines []logLine, tailLines, contextLines int) []logRange {
+	stepAt := func(t time.Time) string {
+		name := ""
+		for _, step := range job.Steps {
+			if stepContains(step, t) {
+				name = step.GetName()
+			}
+		}
+		return name
+	}
+
+	var ranges []logRange
+	for i, line := range lines {
+		if strings.Contains(line.text, "##[error]") {
+			ranges = append(ranges, logRange{start: max(i-contextLines, 0), end: i, reason: "error annotation", step: stepAt(line.time)})
+		}
+	}
+
+	failedSteps := 0
+	for _, step := range job.Steps {
+		if step.GetConclusion() != "failure" {
+			continue
+		}
+		first, last := -1, -1
+		for i, line := range lines {
+			if stepContains(step, line.time) {
+				if first < 0 {
+					first = i
+				}
+				last = i
+			}
+		}
+		if last < 0 || tailLines == 0 {
+			continue
+		}
+		failedSteps++
+		ranges = append(ranges, logRange{start: max(first, last-tailLines+1), end: last, reason: "end of failed step", step: step.GetName()})
+	}
+	// without timestamps the failed steps cannot be located, fall back to
+	// the end of the log
+	if failedSteps == 0 && job.GetConclusion() == "failure" && len(lines) > 0 && tailLines > 0 {
+		ranges = append(ranges, logRange{start: max(len(lines)-tailLines, 0), end: len(lines) - 1, reason: "end of log"})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+	var merged []logRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.start <= merged[n-1].end+1 {
+			prev := &merged[n-1]
+			prev.end = max(prev.end, r.end)
+			if !strings.Contains(prev.reason, r.reason) {
+				prev.reason += ", " + r.reason
+			}
+			if prev.step == "" {
+				prev.step = r.step
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
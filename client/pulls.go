@@ -0,0 +1,244 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+	"github.com/google/go-github/v74/github"
+)
+
+const (
+	defaultMaxPatchSize = 10000
+	// maxPullRequestFiles is the number of files GitHub lists for a pull request.
+	maxPullRequestFiles = 3000
+)
+
+// truncatePatch cuts the patch of file to maxSize bytes at a line boundary.
+func truncatePatch(file *model.CommitFileInfo, maxSize int) bool {
+	if maxSize < 0 {
+		file.PatchSize = len(file.Patch)
+		file.Patch = ""
+		return false
+	}
+	if len(file.Patch) <= maxSize {
+		return false
+	}
+	file.PatchSize = len(file.Patch)
+	// a line ending exactly at maxSize is kept whole
+	if i := strings.LastIndexByte(file.Patch[:maxSize+1], '\n'); i >= 0 {
+		file.Patch = file.Patch[:i]
+	} else {
+		file.Patch = file.Patch[:maxSize]
+	}
+	file.PatchTruncated = true
+	return true
+}
+
+// ListPullRequestFiles returns the changed files of a pull request from all
+// pages, with each patch capped to MaxPatchSize.
+func (c *GithubClient) ListPullRequestFiles(ctx context.Context, opt model.ListPullRequestFilesOption) (*model.PullRequestFilesResult, error) {
+	if opt.MaxPatchSize == 0 {
+		opt.MaxPatchSize = defaultMaxPatchSize
+	}
+	for _, glob := range []string{opt.Include, opt.Exclude} {
+		if _, err := matchGlob(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern: %v", err)
+		}
+	}
+
+	result := &model.PullRequestFilesResult{
+		Files: make([]model.CommitFileInfo, 0),
+	}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		files, resp, err := c.c.PullRequests.ListFiles(ctx, opt.Owner, opt.Repository, opt.Number, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			result.TotalFiles++
+			if !includePath(file.GetFilename(), opt.Include, opt.Exclude) {
+				continue
+			}
+			fileInfo := commitFileInfo(file)
+			if truncatePatch(&fileInfo, opt.MaxPatchSize) {
+				result.TruncatedPatches++
+			}
+			result.Files = append(result.Files, fileInfo)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	result.FileLimitReached = result.TotalFiles >= maxPullRequestFiles
+
+	return result, nil
+}
+
+func (c *GithubClient) ListPullRequestReviews(ctx context.Context, opt model.ListPullRequestReviewsOption) (*model.PullRequestReviewsResult, error) {
+	if opt.ResultPerpage == 0 {
+		opt.ResultPerpage = c.pageSize
+	}
+	if opt.Page == 0 {
+		opt.Page = 1
+	}
+	reviews, resp, err := c.c.PullRequests.ListReviews(ctx, opt.Owner, opt.Repository, opt.Number, &github.ListOptions{
+		PerPage: opt.ResultPerpage,
+		Page:    opt.Page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.PullRequestReviewsResult{
+		NextPage: resp.NextPage,
+		LastPage: resp.LastPage,
+		Reviews:  make([]model.PullRequestReviewInfo, 0),
+	}
+	for _, review := range reviews {
+		result.Reviews = append(result.Reviews, model.PullRequestReviewInfo{
+			ID:                review.GetID(),
+			State:             review.GetState(),
+			User:              review.GetUser().GetLogin(),
+			AuthorAssociation: review.GetAuthorAssociation(),
+			Body:              review.GetBody(),
+			SubmittedAt:       formatTimestamp(review.SubmittedAt),
+			CommitID:          review.GetCommitID(),
+			HTMLURL:           review.GetHTMLURL(),
+		})
+	}
+
+	return result, nil
+}
+
+func reviewCommentInfo(comment *github.PullRequestComment) model.ReviewCommentInfo {
+	return model.ReviewCommentInfo{
+		ID:               comment.GetID(),
+		ReviewID:         comment.GetPullRequestReviewID(),
+		InReplyTo:        comment.GetInReplyTo(),
+		User:             comment.GetUser().GetLogin(),
+		Body:             comment.GetBody(),
+		Path:             comment.GetPath(),
+		Line:             comment.GetLine(),
+		StartLine:        comment.GetStartLine(),
+		Side:             comment.GetSide(),
+		StartSide:        comment.GetStartSide(),
+		Outdated:         comment.Line == nil && comment.GetSubjectType() != "file",
+		OriginalLine:     comment.GetOriginalLine(),
+		SubjectType:      comment.GetSubjectType(),
+		DiffHunk:         comment.GetDiffHunk(),
+		CommitID:         comment.GetCommitID(),
+		OriginalCommitID: comment.GetOriginalCommitID(),
+		CreatedAt:        formatTimestamp(comment.CreatedAt),
+		UpdatedAt:        formatTimestamp(comment.UpdatedAt),
+		HTMLURL:          comment.GetHTMLURL(),
+	}
+}
+
+// ListPullRequestReviewComments returns the review comments of a pull request
+// from all pages as threads, with replies nested below the comment they answer.
+func (c *GithubClient) ListPullRequestReviewComments(ctx context.Context, opt model.ListPullRequestReviewCommentsOption) (*model.PullRequestReviewCommentsResult, error) {
+	since, err := parseTimestamp("since", opt.Since)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := matchGlob(opt.Path, ""); err != nil {
+		return nil, fmt.Errorf("invalid glob pattern: %v", err)
+	}
+
+	var comments []*github.PullRequestComment
+	opts := &github.PullRequestListCommentsOptions{
+		Sort:        "created",
+		Direction:   "asc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		page, resp, err := c.c.PullRequests.ListComments(ctx, opt.Owner, opt.Repository, opt.Number, opts)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	threads := reviewThreads(comments)
+	result := &model.PullRequestReviewCommentsResult{
+		TotalComments: len(comments),
+		Threads:       make([]model.ReviewCommentInfo, 0),
+	}
+	for _, thread := range threads {
+		if opt.Path != "" && !includePath(thread.Path, opt.Path, "") {
+			continue
+		}
+		if opt.ReviewID != 0 && !threadMatches(thread, func(comment model.ReviewCommentInfo) bool {
+			return comment.ReviewID == opt.ReviewID
+		}) {
+			continue
+		}
+		if !since.IsZero() && !threadMatches(thread, func(comment model.ReviewCommentInfo) bool {
+			updated, err := time.Parse(time.RFC3339, comment.UpdatedAt)
+			return err == nil && updated.After(since)
+		}) {
+			continue
+		}
+		result.Threads = append(result.Threads, thread)
+	}
+
+	return result, nil
+}
+
+// reviewThreads nests every comment below the comment it replies to. Replies
+// share the diff hunk of the thread, so it is only kept on the first comment.
+// Comments replying to a deleted comment start a thread of their own.
+func reviewThreads(comments []*github.PullRequestComment) []model.ReviewCommentInfo {
+	ids := make(map[int64]bool, len(comments))
+	for _, comment := range comments {
+		ids[comment.GetID()] = true
+	}
+	replies := make(map[int64][]*github.PullRequestComment)
+	var roots []*github.PullRequestComment
+	for _, comment := range comments {
+		if parent := comment.GetInReplyTo(); parent != 0 && ids[parent] && parent != comment.GetID() {
+			replies[parent] = append(replies[parent], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+
+	var nest func(comment *github.PullRequestComment, depth int) model.ReviewCommentInfo
+	nest = func(comment *github.PullRequestComment, depth int) model.ReviewCommentInfo {
+		info := reviewCommentInfo(comment)
+		if depth > 0 {
+			info.DiffHunk = ""
+		}
+		for _, reply := range replies[comment.GetID()] {
+			info.Replies = append(info.Replies, nest(reply, depth+1))
+		}
+		return info
+	}
+	threads := make([]model.ReviewCommentInfo, 0, len(roots))
+	for _, root := range roots {
+		threads = append(threads, nest(root, 0))
+	}
+	return threads
+}
+
+// threadMatches reports whether any comment of a thread matches.
+func threadMatches(thread model.ReviewCommentInfo, match func(model.ReviewCommentInfo) bool) bool {
+	if match(thread) {
+		return true
+	}
+	for _, reply := range thread.Replies {
+		if threadMatches(reply, match) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+// TestListPullRequestFiles tests that files are listed across pages, filtered by globs and have their patches capped
+func TestListPullRequestFiles(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/o/r/pulls/3/files" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"filename": "docs/readme.md", "status": "modified", "patch": "@@ -1 +1 @@\n-a\n+b"},
+			})
+			return
+		}
+		w.Header().Set("Link", `<http://`+r.Host+r.URL.Path+`?page=2>; rel="next"`)
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"filename": "main.go", "status": "modified", "additions": 2, "patch": "@@ -1,2 +1,2 @@\n-old line\n+new line\n context"},
+			{"filename": "main_test.go", "status": "added", "patch": "@@ -0,0 +1 @@\n+package main"},
+		})
+	})
+
+	files, err := client.ListPullRequestFiles(context.Background(), model.ListPullRequestFilesOption{
+		Owner: "o", Repository: "r", Number: 3, Include: "*.go", Exclude: "*_test.go", MaxPatchSize: 25,
+	})
+	if err != nil {
+		t.Fatalf("ListPullRequestFiles failed: %v", err)
+	}
+	if files.TotalFiles != 3 || len(files.Files) != 1 || files.FileLimitReached {
+		t.Fatalf("Unexpected files: %+v", files)
+	}
+	file := files.Files[0]
+	if file.Filename != "main.go" || file.Patch != "@@ -1,2 +1,2 @@\n-old line" || !file.PatchTruncated || file.PatchSize != 44 || files.TruncatedPatches != 1 {
+		t.Errorf("Unexpected patch cap: %+v", file)
+	}
+
+	files, err = client.ListPullRequestFiles(context.Background(), model.ListPullRequestFilesOption{Owner: "o", Repository: "r", Number: 3, MaxPatchSize: -1})
+	if err != nil {
+		t.Fatalf("ListPullRequestFiles failed: %v", err)
+	}
+	if len(files.Files) != 3 || files.Files[2].Filename != "docs/readme.md" || files.Files[2].Patch != "" || files.TruncatedPatches != 0 {
+		t.Errorf("Expected all files without patches, got %+v", files)
+	}
+
+	if _, err := client.ListPullRequestFiles(context.Background(), model.ListPullRequestFilesOption{Owner: "o", Repository: "r", Number: 3, Include: "["}); err == nil {
+		t.Errorf("Expected an error for an invalid glob")
+	}
+}
+
+// TestListPullRequestReviewComments tests that review comments are nested into threads and filtered by thread
+func TestListPullRequestReviewComments(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/o/r/pulls/3/comments" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 3, "in_reply_to_id": 1, "pull_request_review_id": 11, "body": "done", "path": "main.go", "diff_hunk": "@@ -1 +1 @@", "line": 4, "user": map[string]interface{}{"login": "alice"}, "updated_at": "2024-05-03T10:00:00Z"},
+				{"id": 4, "in_reply_to_id": 99, "pull_request_review_id": 12, "body": "orphan", "path": "docs/readme.md", "original_line": 2, "updated_at": "2024-05-01T10:00:00Z"},
+			})
+			return
+		}
+		w.Header().Set("Link", `<http://`+r.Host+r.URL.Path+`?page=2>; rel="next"`)
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"id": 1, "pull_request_review_id": 10, "body": "rename this", "path": "main.go", "diff_hunk": "@@ -1 +1 @@", "line": 4, "side": "RIGHT", "user": map[string]interface{}{"login": "bob"}, "updated_at": "2024-05-01T10:00:00Z"},
+			{"id": 2, "in_reply_to_id": 1, "pull_request_review_id": 11, "body": "why?", "path": "main.go", "diff_hunk": "@@ -1 +1 @@", "line": 4, "user": map[string]interface{}{"login": "alice"}, "updated_at": "2024-05-01T11:00:00Z"},
+		})
+	})
+
+	comments, err := client.ListPullRequestReviewComments(context.Background(), model.ListPullRequestReviewCommentsOption{Owner: "o", Repository: "r", Number: 3})
+	if err != nil {
+		t.Fatalf("ListPullRequestReviewComments failed: %v", err)
+	}
+	if comments.TotalComments != 4 || len(comments.Threads) != 2 {
+		t.Fatalf("Expected 4 comments in 2 threads, got %+v", comments)
+	}
+	thread := comments.Threads[0]
+	if thread.ID != 1 || thread.Side != "RIGHT" || thread.DiffHunk == "" || len(thread.Replies) != 2 || thread.Replies[0].ID != 2 || thread.Replies[1].ID != 3 {
+		t.Errorf("Unexpected thread: %+v", thread)
+	}
+	if thread.Replies[0].DiffHunk != "" || thread.Replies[0].InReplyTo != 1 {
+		t.Errorf("Expected replies without the diff hunk, got %+v", thread.Replies[0])
+	}
+	if orphan := comments.Threads[1]; orphan.ID != 4 || !orphan.Outdated || orphan.OriginalLine != 2 {
+		t.Errorf("Expected the reply to a deleted comment to start an outdated thread, got %+v", orphan)
+	}
+
+	comments, err = client.ListPullRequestReviewComments(context.Background(), model.ListPullRequestReviewCommentsOption{Owner: "o", Repository: "r", Number: 3, ReviewID: 11, Since: "2024-05-02"})
+	if err != nil {
+		t.Fatalf("ListPullRequestReviewComments failed: %v", err)
+	}
+	if len(comments.Threads) != 1 || comments.Threads[0].ID != 1 || len(comments.Threads[0].Replies) != 2 {
+		t.Errorf("Expected the whole thread with a matching reply, got %+v", comments.Threads)
+	}
+
+	comments, err = client.ListPullRequestReviewComments(context.Background(), model.ListPullRequestReviewCommentsOption{Owner: "o", Repository: "r", Number: 3, Path: "docs/**"})
+	if err != nil {
+		t.Fatalf("ListPullRequestReviewComments failed: %v", err)
+	}
+	if len(comments.Threads) != 1 || comments.Threads[0].ID != 4 {
+		t.Errorf("Expected only the docs thread, got %+v", comments.Threads)
+	}
+}
+
+// TestGetPullRequestStatus tests that mergeability is polled and checks, reviews and protection turn into blockers
+func TestGetPullRequestStatus(t *testing.T) {
+	interval := mergeablePollInterval
+	mergeablePollInterval = 10 * time.Millisecond
+	defer func() { mergeablePollInterval = interval }()
+
+	polls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/pulls/3":
+			polls++
+			pr := map[string]interface{}{
+				"number": 3, "state": "open", "mergeable_state": "unknown",
+				"base":                map[string]interface{}{"ref": "main"},
+				"head":                map[string]interface{}{"ref": "feature", "sha": "abc"},
+				"requested_reviewers": []map[string]interface{}{{"login": "carol"}},
+			}
+			if polls > 1 {
+				pr["mergeable"] = true
+				pr["mergeable_state"] = "blocked"
+			}
+			json.NewEncoder(w).Encode(pr)
+		case "/api/v3/repos/o/r/branches/main":
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": "main", "protected": true})
+		case "/api/v3/repos/o/r/branches/main/protection":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"required_status_checks":        map[string]interface{}{"strict": true, "checks": []map[string]interface{}{{"context": "build"}, {"context": "lint"}}},
+				"required_pull_request_reviews": map[string]interface{}{"required_approving_review_count": 2},
+			})
+		case "/api/v3/repos/o/r/commits/abc/status":
+			json.NewEncoder(w).Encode(map[string]interface{}{"statuses": []map[string]interface{}{{"context": "coverage", "state": "failure"}}})
+		case "/api/v3/repos/o/r/commits/abc/check-runs":
+			json.NewEncoder(w).Encode(map[string]interface{}{"total_count": 1, "check_runs": []map[string]interface{}{{"name": "build", "status": "completed", "conclusion": "success"}}})
+		case "/api/v3/repos/o/r/pulls/3/reviews":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"state": "CHANGES_REQUESTED", "user": map[string]interface{}{"login": "alice"}},
+				{"state": "APPROVED", "user": map[string]interface{}{"login": "bob"}},
+				{"state": "APPROVED", "user": map[string]interface{}{"login": "alice"}},
+				{"state": "COMMENTED", "user": map[string]interface{}{"login": "alice"}},
+			})
+		case "/api/v3/repos/o/r/compare/main...abc":
+			json.NewEncoder(w).Encode(map[string]interface{}{"ahead_by": 2, "behind_by": 5})
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	})
+
+	status, err := client.GetPullRequestStatus(context.Background(), model.GetPullRequestStatusOption{Owner: "o", Repository: "r", Number: 3})
+	if err != nil {
+		t.Fatalf("GetPullRequestStatus failed: %v", err)
+	}
+	if polls != 2 || status.Mergeable != "mergeable" || status.MergeableState != "blocked" {
+		t.Errorf("Expected mergeability to be polled until known, got %d polls and %+v", polls, status)
+	}
+	if !status.Protected || status.ProtectionPartial || len(status.RequiredChecks) != 1 || len(status.OptionalChecks) != 1 || status.OptionalState != "failure" {
+		t.Errorf("Unexpected checks: %+v", status)
+	}
+	if status.RequiredState != "pending" || len(status.MissingRequired) != 1 || status.MissingRequired[0] != "lint" {
+		t.Errorf("Expected the missing lint check to keep the required state pending, got %+v", status)
+	}
+	if status.ReviewDecision != "APPROVED" || status.Approvals != 2 || status.RequiredApprovals != 2 || len(status.ChangesRequestedBy) != 0 {
+		t.Errorf("Expected the latest review of each reviewer to count, got %+v", status)
+	}
+	if status.AheadBy != 2 || status.BehindBy != 5 || len(status.RequestedReviewers) != 1 {
+		t.Errorf("Unexpected comparison: %+v", status)
+	}
+	expected := []string{"required check lint has not reported", "branch is 5 commits behind main and must be up to date"}
+	if len(status.Blockers) != len(expected) {
+		t.Fatalf("Expected blockers %v, got %v", expected, status.Blockers)
+	}
+	for i, blocker := range expected {
+		if status.Blockers[i] != blocker {
+			t.Errorf("Expected blocker %q, got %q", blocker, status.Blockers[i])
+		}
+	}
+}
+
+// TestSearchPullRequests tests that merged status comes from the search result and hydration fills in the pull request
+func TestSearchPullRequests(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/search/issues":
+			repoURL := "http://" + r.Host + "/api/v3/repos/o/r"
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"total_count": 2,
+				"items": []map[string]interface{}{
+					{"number": 1, "title": "Fix parser", "state": "closed", "repository_url": repoURL, "pull_request": map[string]interface{}{"url": "u", "merged_at": "2024-05-01T10:00:00Z"}},
+					{"number": 2, "title": "Merge branch main", "state": "closed", "repository_url": repoURL, "pull_request": map[string]interface{}{"url": "u"}},
+				},
+			})
+		case "/api/v3/repos/o/r/pulls/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"number": 1, "title": "Fix parser", "state": "closed", "merged": true, "merged_at": "2024-05-01T10:00:00Z",
+				"additions": 10, "deletions": 2, "base": map[string]interface{}{"ref": "main"}, "head": map[string]interface{}{"ref": "fix"},
+			})
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	})
+
+	prs, err := client.SearchPullRequests(context.Background(), model.SearchPROption{Query: "repo:o/r"})
+	if err != nil {
+		t.Fatalf("SearchPullRequests failed: %v", err)
+	}
+	if len(prs.PRs) != 2 || !prs.PRs[0].Merged || prs.PRs[0].MergedAt != "2024-05-01T10:00:00Z" || prs.PRs[1].Merged {
+		t.Errorf("Expected merged status from merged_at, got %+v", prs.PRs)
+	}
+
+	prs, err = client.SearchPullRequests(context.Background(), model.SearchPROption{Query: "repo:o/r", Hydrate: true})
+	if err != nil {
+		t.Fatalf("SearchPullRequests failed: %v", err)
+	}
+	if pr := prs.PRs[0]; pr.BaseRef != "main" || pr.HeadRef != "fix" || pr.Additions != 10 || !pr.Merged {
+		t.Errorf("Expected the hydrated pull request, got %+v", pr)
+	}
+	if prs.PRs[1].Number != 2 || len(prs.HydrateErrors) != 1 {
+		t.Errorf("Expected the failed pull request to keep its search result, got %+v", prs)
+	}
+}
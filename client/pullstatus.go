@@ -0,0 +1,267 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+	"github.com/google/go-github/v74/github"
+)
+
+const (
+	defaultMergeableWait = 10
+	maxMergeableWait     = 60
+)
+
+// mergeablePollInterval is how often a pull request is fetched again while
+// GitHub computes its mergeability.
+var mergeablePollInterval = time.Second
+
+// GetPullRequestStatus summarizes what stands between a pull request and its
+// merge: mergeability, required and optional checks, reviews, branch
+// protection and how far the head is behind the base branch.
+func (c *GithubClient) GetPullRequestStatus(ctx context.Context, opt model.GetPullRequestStatusOption) (*model.PullRequestStatusResult, error) {
+	if opt.MaxWait == 0 {
+		opt.MaxWait = defaultMergeableWait
+	}
+	opt.MaxWait = min(opt.MaxWait, maxMergeableWait)
+
+	pr, err := c.waitMergeable(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.PullRequestStatusResult{
+		Number:         pr.GetNumber(),
+		State:          pr.GetState(),
+		Draft:          pr.GetDraft(),
+		Merged:         pr.GetMerged(),
+		BaseRef:        pr.GetBase().GetRef(),
+		HeadRef:        pr.GetHead().GetRef(),
+		HeadSHA:        pr.GetHead().GetSHA(),
+		Mergeable:      "unknown",
+		MergeableState: pr.GetMergeableState(),
+		RequiredChecks: make([]model.CheckInfo, 0),
+		OptionalChecks: make([]model.CheckInfo, 0),
+	}
+	if pr.Mergeable != nil {
+		result.Mergeable = "conflicting"
+		if pr.GetMergeable() {
+			result.Mergeable = "mergeable"
+		}
+	}
+	for _, reviewer := range pr.RequestedReviewers {
+		result.RequestedReviewers = append(result.RequestedReviewers, reviewer.GetLogin())
+	}
+	for _, team := range pr.RequestedTeams {
+		result.RequestedReviewers = append(result.RequestedReviewers, team.GetSlug())
+	}
+
+	protection, partial, err := c.branchProtection(ctx, opt.Owner, opt.Repository, result.BaseRef)
+	if err != nil {
+		return nil, err
+	}
+	result.Protected = protection != nil
+	result.ProtectionPartial = partial
+
+	checks, truncated, err := c.commitChecks(ctx, opt.Owner, opt.Repository, result.HeadSHA)
+	if err != nil {
+		return nil, err
+	}
+	result.ChecksLimitReached = truncated
+	required := requiredChecks(protection)
+	reported := make(map[string]bool)
+	for _, check := range checks {
+		if required[check.Name] {
+			reported[check.Name] = true
+			result.RequiredChecks = append(result.RequiredChecks, check)
+		} else {
+			result.OptionalChecks = append(result.OptionalChecks, check)
+		}
+	}
+	for name := range required {
+		if !reported[name] {
+			result.MissingRequired = append(result.MissingRequired, name)
+		}
+	}
+	sort.Strings(result.MissingRequired)
+	result.RequiredState = rollupState(result.RequiredChecks)
+	if len(result.MissingRequired) > 0 && result.RequiredState != checkStateFailure {
+		result.RequiredState = checkStatePending
+	}
+	result.OptionalState = rollupState(result.OptionalChecks)
+	if truncated {
+		result.RequiredState = truncatedState(result.RequiredState)
+		result.OptionalState = truncatedState(result.OptionalState)
+	}
+
+	if protection != nil && protection.RequiredPullRequestReviews != nil {
+		result.RequiredApprovals = protection.RequiredPullRequestReviews.RequiredApprovingReviewCount
+	}
+	if err := c.reviewDecision(ctx, opt, result); err != nil {
+		return nil, err
+	}
+
+	comparison, _, err := c.c.Repositories.CompareCommits(ctx, opt.Owner, opt.Repository, result.BaseRef, result.HeadSHA, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare with base branch: %w", err)
+	}
+	result.AheadBy = comparison.GetAheadBy()
+	result.BehindBy = comparison.GetBehindBy()
+
+	result.Blockers = mergeBlockers(result, protection)
+	return result, nil
+}
+
+// waitMergeable fetches a pull request until GitHub has computed whether it
+// can be merged, giving up after MaxWait seconds.
+func (c *GithubClient) waitMergeable(ctx context.Context, opt model.GetPullRequestStatusOption) (*github.PullRequest, error) {
+	deadline := time.Now().Add(time.Duration(opt.MaxWait) * time.Second)
+	for {
+		pr, _, err := c.c.PullRequests.Get(ctx, opt.Owner, opt.Repository, opt.Number)
+		if err != nil {
+			return nil, err
+		}
+		if pr.Mergeable != nil || pr.GetState() != "open" || !time.Now().Add(mergeablePollInterval).Before(deadline) {
+			return pr, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(mergeablePollInterval):
+		}
+	}
+}
+
+// branchProtection returns the protection of a branch, or nil when it is not
+// protected. Reading the protection needs admin access, without it only the
+// required status checks listed with the branch are returned and partial is set.
+func (c *GithubClient) branchProtection(ctx context.Context, owner, repo, branch string) (protection *github.Protection, partial bool, err error) {
+	b, _, err := c.c.Repositories.GetBranch(ctx, owner, repo, branch, 1)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get base branch: %w", err)
+	}
+	if !b.GetProtected() {
+		return nil, false, nil
+	}
+	protection, _, err = c.c.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && (errResp.Response.StatusCode == http.StatusForbidden || errResp.Response.StatusCode == http.StatusNotFound) {
+		if b.Protection == nil {
+			return &github.Protection{}, true, nil
+		}
+		return b.Protection, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return protection, false, nil
+}
+
+func requiredChecks(protection *github.Protection) map[string]bool {
+	required := make(map[string]bool)
+	if protection == nil || protection.RequiredStatusChecks == nil {
+		return required
+	}
+	if protection.RequiredStatusChecks.Checks != nil {
+		for _, check := range *protection.RequiredStatusChecks.Checks {
+			required[check.Context] = true
+		}
+	}
+	if protection.RequiredStatusChecks.Contexts != nil {
+		for _, name := range *protection.RequiredStatusChecks.Contexts {
+			required[name] = true
+		}
+	}
+	return required
+}
+
+// reviewDecision counts the latest approving or change requesting review of
+// every reviewer, the way GitHub decides whether reviews block a merge.
+func (c *GithubClient) reviewDecision(ctx context.Context, opt model.GetPullRequestStatusOption, result *model.PullRequestStatusResult) error {
+	latest := make(map[string]string)
+	var order []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		reviews, resp, err := c.c.PullRequests.ListReviews(ctx, opt.Owner, opt.Repository, opt.Number, opts)
+		if err != nil {
+			return err
+		}
+		for _, review := range reviews {
+			state := review.GetState()
+			// comments do not change the verdict of a reviewer
+			if state != "APPROVED" && state != "CHANGES_REQUESTED" && state != "DISMISSED" {
+				continue
+			}
+			user := review.GetUser().GetLogin()
+			if _, ok := latest[user]; !ok {
+				order = append(order, user)
+			}
+			latest[user] = state
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	for _, user := range order {
+		switch latest[user] {
+		case "APPROVED":
+			result.Approvals++
+		case "CHANGES_REQUESTED":
+			result.ChangesRequestedBy = append(result.ChangesRequestedBy, user)
+		}
+	}
+	switch {
+	case len(result.ChangesRequestedBy) > 0:
+		result.ReviewDecision = "CHANGES_REQUESTED"
+	case result.Approvals < result.RequiredApprovals:
+		result.ReviewDecision = "REVIEW_REQUIRED"
+	case result.Approvals > 0:
+		result.ReviewDecision = "APPROVED"
+	}
+	return nil
+}
+
+func mergeBlockers(result *model.PullRequestStatusResult, protection *github.Protection) []string {
+	blockers := make([]string, 0)
+	if result.Merged {
+		return append(blockers, "pull request is already merged")
+	}
+	if result.State != "open" {
+		return append(blockers, "pull request is "+result.State)
+	}
+	if result.Draft {
+		blockers = append(blockers, "pull request is a draft")
+	}
+	if result.Mergeable == "conflicting" {
+		blockers = append(blockers, "merge conflicts with "+result.BaseRef)
+	}
+	for _, check := range result.RequiredChecks {
+		if check.State != checkStateSuccess {
+			blockers = append(blockers, fmt.Sprintf("required check %s is %s", check.Name, check.State))
+		}
+	}
+	for _, name := range result.MissingRequired {
+		blockers = append(blockers, fmt.Sprintf("required check %s has not reported", name))
+	}
+	for _, user := range result.ChangesRequestedBy {
+		blockers = append(blockers, "changes requested by "+user)
+	}
+	if result.Approvals < result.RequiredApprovals {
+		blockers = append(blockers, fmt.Sprintf("needs %d approving reviews, has %d", result.RequiredApprovals, result.Approvals))
+	}
+	if result.BehindBy > 0 && protection != nil && protection.RequiredStatusChecks != nil && protection.RequiredStatusChecks.Strict {
+		blockers = append(blockers, fmt.Sprintf("branch is %d commits behind %s and must be up to date", result.BehindBy, result.BaseRef))
+	}
+	// rules this tool cannot see, like code owner reviews or rulesets
+	if len(blockers) == 0 && result.MergeableState == "blocked" {
+		blockers = append(blockers, "blocked by branch protection or repository rules")
+	}
+	return blockers
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+	"github.com/google/go-github/v74/github"
+)
+
+const (
+	defaultRateLimitRetries = 3
+	defaultRateLimitMaxWait = 30 * time.Second
+
+	// maxErrorBodySize bounds how much of a 403/429 body is read to tell
+	// secondary rate limits from other forbidden responses.
+	maxErrorBodySize = 64 << 10
+)
+
+// RateLimitError is returned when GitHub refuses a request because of a rate
+// limit. Primary limits fail immediately and report when the quota resets,
+// secondary limits are only reported after the retries are used up.
+type RateLimitError struct {
+	Resource   string
+	Limit      int
+	Reset      time.Time
+	Secondary  bool
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Secondary {
+		return fmt.Sprintf("github secondary rate limit exceeded, retry after %s", e.RetryAfter)
+	}
+	return fmt.Sprintf("github %s rate limit of %d requests exceeded, resets at %s",
+		e.Resource, e.Limit, e.Reset.Format(time.RFC3339))
+}
+
+// rateLimitTransport retries requests hitting a secondary rate limit after the
+// wait GitHub asks for, and turns primary rate limit responses into a
+// RateLimitError without retrying.
+type rateLimitTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	maxWait    time.Duration
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			resp.Body.Close()
+			return nil, primaryRateLimitError(resp)
+		}
+
+		wait, secondary, err := secondaryRateLimitWait(resp, attempt)
+		if err != nil {
+			return nil, err
+		}
+		if !secondary {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if attempt >= t.maxRetries || wait > t.maxWait {
+			return nil, &RateLimitError{Secondary: true, RetryAfter: wait}
+		}
+
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return nil, &RateLimitError{Secondary: true, RetryAfter: wait}
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func primaryRateLimitError(resp *http.Response) *RateLimitError {
+	e := &RateLimitError{
+		Resource: resp.Header.Get("X-RateLimit-Resource"),
+	}
+	if e.Resource == "" {
+		e.Resource = "core"
+	}
+	e.Limit, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		e.Reset = time.Unix(reset, 0).UTC()
+	}
+	return e
+}
+
+// secondaryRateLimitWait reports whether resp is a secondary rate limit and
+// how long to wait before retrying. The body of resp stays readable.
+func secondaryRateLimitWait(resp *http.Response, attempt int) (time.Duration, bool, error) {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		seconds, err := strconv.Atoi(retryAfter)
+		if err == nil {
+			return time.Duration(seconds) * time.Second, true, nil
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	resp.Body.Close()
+	if err != nil {
+		return 0, false, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	lower := strings.ToLower(string(body))
+	if !strings.Contains(lower, "secondary rate limit") && !strings.Contains(lower, "abuse detection") {
+		return 0, false, nil
+	}
+	// no hint from GitHub, back off exponentially
+	return time.Second << attempt, true, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// GetRateLimit reports the remaining quotas, the call itself does not count against them.
+func (c *GithubClient) GetRateLimit(ctx context.Context, opt model.RateLimitOption) (*model.RateLimitResult, error) {
+	limits, _, err := c.c.RateLimit.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.RateLimitResult{
+		Core:       rateInfo(limits.GetCore()),
+		Search:     rateInfo(limits.GetSearch()),
+		CodeSearch: rateInfo(limits.GetCodeSearch()),
+		GraphQL:    rateInfo(limits.GetGraphQL()),
+	}
+	return result, nil
+}
+
+func rateInfo(rate *github.Rate) *model.RateInfo {
+	if rate == nil {
+		return nil
+	}
+	return &model.RateInfo{
+		Limit:     rate.Limit,
+		Remaining: rate.Remaining,
+		Used:      rate.Used,
+		Reset:     rate.Reset.Format(time.RFC3339),
+	}
+}
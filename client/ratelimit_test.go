@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+// TestSecondaryRateLimitRetry tests that a secondary rate limit is waited out and retried
+func TestSecondaryRateLimitRetry(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, `{"message":"You have exceeded a secondary rate limit."}`, http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "main", "commit": map[string]interface{}{"sha": "abc"}})
+	}, WithRateLimitRetry(2, time.Second))
+
+	branch, err := client.GetBranchByName(context.Background(), model.GetBranchByNameOption{Owner: "o", Repository: "r", BranchName: "main"})
+	if err != nil {
+		t.Fatalf("GetBranchByName failed: %v", err)
+	}
+	if branch.CommitSHA != "abc" {
+		t.Errorf("Expected commit sha abc, got %s", branch.CommitSHA)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 requests, got %d", calls)
+	}
+}
+
+// TestSecondaryRateLimitTooLong tests that waits longer than the maximum fail without retrying
+func TestSecondaryRateLimitTooLong(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "120")
+		http.Error(w, `{"message":"You have exceeded a secondary rate limit."}`, http.StatusForbidden)
+	}, WithRateLimitRetry(2, time.Second))
+
+	_, err := client.GetBranchByName(context.Background(), model.GetBranchByNameOption{Owner: "o", Repository: "r", BranchName: "main"})
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("Expected a RateLimitError, got %v", err)
+	}
+	if !rateErr.Secondary || rateErr.RetryAfter != 120*time.Second {
+		t.Errorf("Expected secondary limit with retry after 2m0s, got %+v", rateErr)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 request, got %d", calls)
+	}
+}
+
+// TestPrimaryRateLimitFailsFast tests that an exhausted quota is reported with its reset time
+func TestPrimaryRateLimitFailsFast(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.Header().Set("X-RateLimit-Resource", "core")
+		http.Error(w, `{"message":"API rate limit exceeded"}`, http.StatusForbidden)
+	}, WithRateLimitRetry(2, time.Second))
+
+	_, err := client.GetBranchByName(context.Background(), model.GetBranchByNameOption{Owner: "o", Repository: "r", BranchName: "main"})
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("Expected a RateLimitError, got %v", err)
+	}
+	if rateErr.Secondary || rateErr.Limit != 5000 || rateErr.Resource != "core" || !rateErr.Reset.Equal(reset) {
+		t.Errorf("Unexpected rate limit error: %+v", rateErr)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 request, got %d", calls)
+	}
+}
+
+func TestGetRateLimit(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/rate_limit" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"resources": map[string]interface{}{
+				"core":    map[string]interface{}{"limit": 5000, "remaining": 4990, "used": 10, "reset": 1700000000},
+				"search":  map[string]interface{}{"limit": 30, "remaining": 30, "used": 0, "reset": 1700000000},
+				"graphql": map[string]interface{}{"limit": 5000, "remaining": 5000, "used": 0, "reset": 1700000000},
+			},
+		})
+	}, WithRateLimitRetry(2, time.Second))
+
+	limits, err := client.GetRateLimit(context.Background(), model.RateLimitOption{})
+	if err != nil {
+		t.Fatalf("GetRateLimit failed: %v", err)
+	}
+	if limits.Core == nil || limits.Core.Remaining != 4990 || limits.Core.Used != 10 {
+		t.Errorf("Unexpected core quota: %+v", limits.Core)
+	}
+	if limits.Search == nil || limits.Search.Limit != 30 {
+		t.Errorf("Unexpected search quota: %+v", limits.Search)
+	}
+	if limits.CodeSearch != nil {
+		t.Errorf("Expected no code search quota, got %+v", limits.CodeSearch)
+	}
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Felamande/githubMcp/model"
+)
+
+// TestGetTreeFilters tests that tree entries are filtered by path prefix, glob and depth
+func TestGetTreeFilters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/o/r/git/trees/main" || r.URL.Query().Get("recursive") == "" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sha":       "root",
+			"truncated": true,
+			"tree": []map[string]interface{}{
+				{"path": "README.md", "type": "blob", "mode": "100644", "sha": "a1", "size": 10},
+				{"path": "src", "type": "tree", "mode": "040000", "sha": "a2"},
+				{"path": "src/main.go", "type": "blob", "mode": "100644", "sha": "a3", "size": 20},
+				{"path": "src/link.go", "type": "blob", "mode": "120000", "sha": "a4", "size": 7},
+				{"path": "src/pkg", "type": "tree", "mode": "040000", "sha": "a5"},
+				{"path": "src/pkg/util.go", "type": "blob", "mode": "100644", "sha": "a6", "size": 30},
+				{"path": "src/vendor", "type": "commit", "mode": "160000", "sha": "a7"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("", WithEnterpriseURLs(server.URL, ""))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	tree, err := client.GetTree(context.Background(), model.GetTreeOption{Owner: "o", Repository: "r", Ref: "main", Path: "src", MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("GetTree failed: %v", err)
+	}
+	if !tree.Truncated || tree.SHA != "root" {
+		t.Errorf("Expected truncated tree root, got %+v", tree)
+	}
+	types := make(map[string]string)
+	for _, entry := range tree.Entries {
+		types[entry.Path] = entry.Type
+	}
+	expected := map[string]string{"src/main.go": "file", "src/link.go": "symlink", "src/pkg": "dir", "src/vendor": "submodule"}
+	if len(types) != len(expected) {
+		t.Errorf("Expected entries %v, got %v", expected, types)
+	}
+	for path, typ := range expected {
+		if types[path] != typ {
+			t.Errorf("Expected %s to be a %s, got %q", path, typ, types[path])
+		}
+	}
+
+	tree, err = client.GetTree(context.Background(), model.GetTreeOption{Owner: "o", Repository: "r", Ref: "main", Pattern: "**/*.go", MaxEntries: 2})
+	if err != nil {
+		t.Fatalf("GetTree failed: %v", err)
+	}
+	if tree.TotalMatches != 3 || len(tree.Entries) != 2 || !tree.EntryLimitReached {
+		t.Errorf("Expected 2 of 3 matches with the limit reached, got %+v", tree)
+	}
+	if tree.Entries[0].SHA != "a3" || tree.Entries[0].Size != 20 {
+		t.Errorf("Unexpected first entry: %+v", tree.Entries[0])
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.go", "src/pkg/util.go", true},
+		{"*.go", "README.md", false},
+		{"src/*.go", "src/main.go", true},
+		{"src/*.go", "src/pkg/util.go", false},
+		{"src/**/*.go", "src/main.go", true},
+		{"src/**/*.go", "src/pkg/deep/util.go", true},
+		{"**/pkg", "src/pkg", true},
+		{"docs/**", "src/main.go", false},
+	}
+	for _, c := range cases {
+		got, err := matchGlob(c.pattern, c.name)
+		if err != nil {
+			t.Fatalf("matchGlob(%q, %q) failed: %v", c.pattern, c.name, err)
+		}
+		if got != c.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", c.pattern, c.name, got, c.want)
+		}
+	}
+	if _, err := matchGlob("[", "a"); err == nil {
+		t.Errorf("Expected an error for a malformed pattern")
+	}
+}
@@ -0,0 +1,287 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the effective settings of the server binary. Values are read
+// from the defaults, then the config file, then the command line flags.
+type Config struct {
+	AuthMode          string `yaml:"auth_mode"`
+	AppID             int64  `yaml:"app_id"`
+	AppInstallationID int64  `yaml:"app_installation_id"`
+	AppPrivateKeyFile string `yaml:"app_private_key_file"`
+
+	TokenEnv                string                   `yaml:"token_env"`
+	TokenFile               string                   `yaml:"token_file"`
+	BaseURL                 string                   `yaml:"base_url"`
+	UploadURL               string                   `yaml:"upload_url"`
+	PageSize                int                      `yaml:"page_size"`
+	DescriptionTruncateSize int                      `yaml:"description_truncate_size"`
+	Toolsets                []string                 `yaml:"toolsets"`
+	ReadOnly                bool                     `yaml:"read_only"`
+	ToolTimeout             time.Duration            `yaml:"tool_timeout"`
+	ToolTimeouts            map[string]time.Duration `yaml:"tool_timeouts"`
+	RateLimitRetries        int                      `yaml:"rate_limit_retries"`
+	RateLimitMaxWait        time.Duration            `yaml:"rate_limit_max_wait"`
+	CacheSize               int                      `yaml:"cache_size"`
+	CacheTTL                time.Duration            `yaml:"cache_ttl"`
+	CacheDir                string                   `yaml:"cache_dir"`
+	DownloadDir             string                   `yaml:"download_dir"`
+	DownloadMaxAge          time.Duration            `yaml:"download_max_age"`
+	Transport               string                   `yaml:"transport"`
+	Addr                    string                   `yaml:"addr"`
+	Endpoint                string                   `yaml:"endpoint"`
+
+	ConfigFile  string `yaml:"-"`
+	PrintConfig bool   `yaml:"-"`
+}
+
+// Default returns the settings used when nothing is configured.
+func Default() *Config {
+	return &Config{
+		AuthMode:                "token",
+		TokenEnv:                "GITHUB_TOKEN",
+		PageSize:                10,
+		DescriptionTruncateSize: 1024,
+		Toolsets:                []string{"all"},
+		ToolTimeout:             time.Minute,
+		RateLimitRetries:        3,
+		RateLimitMaxWait:        30 * time.Second,
+		CacheTTL:                time.Hour,
+		DownloadMaxAge:          7 * 24 * time.Hour,
+		Transport:               "stdio",
+		Addr:                    "127.0.0.1:8080",
+		Endpoint:                "/mcp",
+	}
+}
+
+// Parse builds the configuration from the command line arguments, loading
+// the file given by --config first so that explicit flags override it.
+func Parse(name string, args []string) (*Config, error) {
+	cfg := Default()
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	cfg.registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if cfg.ConfigFile != "" {
+		if err := cfg.loadFile(cfg.ConfigFile); err != nil {
+			return nil, err
+		}
+		// parse again so flags given on the command line win over the file
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.ConfigFile, "config", "", "path of a YAML config file")
+	fs.BoolVar(&c.PrintConfig, "print-config", false, "print the effective configuration and exit")
+	fs.StringVar(&c.AuthMode, "auth-mode", c.AuthMode, "authentication mode: token or app")
+	fs.Int64Var(&c.AppID, "app-id", c.AppID, "GitHub App ID, for the app auth mode")
+	fs.Int64Var(&c.AppInstallationID, "app-installation-id", c.AppInstallationID, "GitHub App installation ID, for the app auth mode")
+	fs.StringVar(&c.AppPrivateKeyFile, "app-private-key-file", c.AppPrivateKeyFile, "PEM file of the GitHub App private key, for the app auth mode")
+	fs.StringVar(&c.TokenEnv, "token-env", c.TokenEnv, "environment variable holding the GitHub token")
+	fs.StringVar(&c.TokenFile, "token-file", c.TokenFile, "file holding the GitHub token, takes precedence over --token-env")
+	fs.StringVar(&c.BaseURL, "base-url", c.BaseURL, "GitHub API base URL, e.g. https://github.example.com/ for GitHub Enterprise Server, default to https://api.github.com/")
+	fs.StringVar(&c.UploadURL, "upload-url", c.UploadURL, "GitHub upload URL, default to the base URL")
+	fs.IntVar(&c.PageSize, "page-size", c.PageSize, "default number of results per page")
+	fs.IntVar(&c.DescriptionTruncateSize, "description-truncate-size", c.DescriptionTruncateSize, "default size of truncating long descriptions")
+	fs.Var((*listFlag)(&c.Toolsets), "toolsets", "comma separated toolsets to enable, or all")
+	fs.BoolVar(&c.ReadOnly, "read-only", c.ReadOnly, "only expose tools that do not modify GitHub")
+	fs.DurationVar(&c.ToolTimeout, "tool-timeout", c.ToolTimeout, "timeout of a tool call, 0 for no timeout")
+	fs.Var((*durationMapFlag)(&c.ToolTimeouts), "tool-timeouts", "comma separated per tool timeouts, e.g. find_tags=5m,read_file=30s")
+	fs.IntVar(&c.RateLimitRetries, "rate-limit-retries", c.RateLimitRetries, "retries of a request hitting a secondary rate limit")
+	fs.DurationVar(&c.RateLimitMaxWait, "rate-limit-max-wait", c.RateLimitMaxWait, "longest wait before retrying a secondary rate limit, longer waits fail immediately")
+	fs.IntVar(&c.CacheSize, "cache-size", c.CacheSize, "number of responses kept in the conditional request cache, 0 (the default) disables the cache")
+	fs.DurationVar(&c.CacheTTL, "cache-ttl", c.CacheTTL, "drop cached responses not validated within this duration, 0 keeps them until evicted")
+	fs.StringVar(&c.CacheDir, "cache-dir", c.CacheDir, "directory to also store cached responses in")
+	fs.StringVar(&c.DownloadDir, "download-dir", c.DownloadDir, "directory downloaded repository archives, job logs and artifacts are kept in, default to a directory below the system temp dir")
+	fs.DurationVar(&c.DownloadMaxAge, "download-max-age", c.DownloadMaxAge, "remove downloads not used within this duration, checked at most hourly in the background after downloads and on startup when --download-dir is set, 0 keeps them forever")
+	fs.StringVar(&c.Transport, "transport", c.Transport, "transport to serve MCP on: stdio or http, http answers each POSTed request and cannot deliver server initiated notifications")
+	fs.StringVar(&c.Addr, "addr", c.Addr, "listen address of the http transport, the endpoint is unauthenticated and calls tools with the server's GitHub credentials, so only expose it to trusted clients")
+	fs.StringVar(&c.Endpoint, "endpoint", c.Endpoint, "request path of the http transport")
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && err != io.EOF {
+		return fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return nil
+}
+
+// Validate checks that all settings hold usable values.
+func (c *Config) Validate() error {
+	switch c.AuthMode {
+	case "token":
+		if c.TokenEnv == "" && c.TokenFile == "" {
+			return fmt.Errorf("one of token_env or token_file must be set")
+		}
+	case "app":
+		if c.AppID <= 0 || c.AppInstallationID <= 0 {
+			return fmt.Errorf("app_id and app_installation_id must be set for the app auth mode")
+		}
+		if c.AppPrivateKeyFile == "" {
+			return fmt.Errorf("app_private_key_file must be set for the app auth mode")
+		}
+	default:
+		return fmt.Errorf("auth_mode must be token or app, got %q", c.AuthMode)
+	}
+	if c.BaseURL != "" && !isHTTPURL(c.BaseURL) {
+		return fmt.Errorf("base_url must be an absolute http(s) URL, got %q", c.BaseURL)
+	}
+	if c.UploadURL != "" {
+		if c.BaseURL == "" {
+			return fmt.Errorf("upload_url requires base_url to be set")
+		}
+		if !isHTTPURL(c.UploadURL) {
+			return fmt.Errorf("upload_url must be an absolute http(s) URL, got %q", c.UploadURL)
+		}
+	}
+	if c.PageSize < 1 || c.PageSize > 100 {
+		return fmt.Errorf("page_size must be between 1 and 100, got %d", c.PageSize)
+	}
+	if c.DescriptionTruncateSize < 1 {
+		return fmt.Errorf("description_truncate_size must be positive, got %d", c.DescriptionTruncateSize)
+	}
+	if len(c.Toolsets) == 0 {
+		return fmt.Errorf("toolsets must not be empty")
+	}
+	if c.ToolTimeout < 0 {
+		return fmt.Errorf("tool_timeout must not be negative, got %s", c.ToolTimeout)
+	}
+	for name, timeout := range c.ToolTimeouts {
+		if timeout < 0 {
+			return fmt.Errorf("tool_timeouts of %s must not be negative, got %s", name, timeout)
+		}
+	}
+	if c.RateLimitRetries < 0 {
+		return fmt.Errorf("rate_limit_retries must not be negative, got %d", c.RateLimitRetries)
+	}
+	if c.RateLimitMaxWait < 0 {
+		return fmt.Errorf("rate_limit_max_wait must not be negative, got %s", c.RateLimitMaxWait)
+	}
+	if c.CacheSize < 0 {
+		return fmt.Errorf("cache_size must not be negative, got %d", c.CacheSize)
+	}
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("cache_ttl must not be negative, got %s", c.CacheTTL)
+	}
+	switch c.Transport {
+	case "stdio":
+	case "http":
+		if c.Addr == "" {
+			return fmt.Errorf("addr must be set for the http transport")
+		}
+		if !strings.HasPrefix(c.Endpoint, "/") {
+			return fmt.Errorf("endpoint must start with /, got %q", c.Endpoint)
+		}
+	default:
+		return fmt.Errorf("transport must be stdio or http, got %q", c.Transport)
+	}
+	return nil
+}
+
+func isHTTPURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Token reads the GitHub token from the configured source. An empty token
+// means anonymous access.
+func (c *Config) Token() (string, error) {
+	if c.TokenFile != "" {
+		data, err := os.ReadFile(c.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %v", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return os.Getenv(c.TokenEnv), nil
+}
+
+// Print writes the effective configuration as YAML.
+func (c *Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// listFlag is a comma separated flag value.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = nil
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// durationMapFlag is a comma separated list of name=duration pairs.
+type durationMapFlag map[string]time.Duration
+
+func (m *durationMapFlag) String() string {
+	var pairs []string
+	for name, d := range *m {
+		pairs = append(pairs, name+"="+d.String())
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (m *durationMapFlag) Set(value string) error {
+	*m = make(map[string]time.Duration)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("expected name=duration, got %q", pair)
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		(*m)[strings.TrimSpace(name)] = d
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestParseDefaults(t *testing.T) {
+	cfg, err := Parse("test", nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Expected defaults %+v, got %+v", Default(), cfg)
+	}
+}
+
+func TestParseFlagsOverrideFile(t *testing.T) {
+	path := writeConfigFile(t, `
+page_size: 50
+description_truncate_size: 200
+toolsets: [repos, issues]
+transport: http
+addr: 127.0.0.1:9000
+`)
+
+	cfg, err := Parse("test", []string{"--config", path, "--page-size=20", "--toolsets=pulls"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.PageSize != 20 {
+		t.Errorf("Expected page size from flag 20, got %d", cfg.PageSize)
+	}
+	if cfg.DescriptionTruncateSize != 200 {
+		t.Errorf("Expected truncate size from file 200, got %d", cfg.DescriptionTruncateSize)
+	}
+	if !reflect.DeepEqual(cfg.Toolsets, []string{"pulls"}) {
+		t.Errorf("Expected toolsets from flag [pulls], got %v", cfg.Toolsets)
+	}
+	if cfg.Transport != "http" || cfg.Addr != "127.0.0.1:9000" {
+		t.Errorf("Expected http transport on 127.0.0.1:9000, got %s on %s", cfg.Transport, cfg.Addr)
+	}
+}
+
+func TestParseValidation(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		file string
+		want string
+	}{
+		{name: "page size", args: []string{"--page-size=0"}, want: "page_size"},
+		{name: "transport", args: []string{"--transport=grpc"}, want: "transport"},
+		{name: "base url", args: []string{"--base-url=github.example.com"}, want: "base_url"},
+		{name: "upload url", args: []string{"--upload-url=https://uploads.example.com/"}, want: "upload_url"},
+		{name: "auth mode", args: []string{"--auth-mode=oauth"}, want: "auth_mode"},
+		{name: "app without key", args: []string{"--auth-mode=app", "--app-id=1", "--app-installation-id=2"}, want: "app_private_key_file"},
+		{name: "unknown field", file: "page_sise: 10\n", want: "page_sise"},
+		{name: "extra args", args: []string{"serve"}, want: "unexpected arguments"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := tc.args
+			if tc.file != "" {
+				args = append([]string{"--config", writeConfigFile(t, tc.file)}, args...)
+			}
+			_, err := Parse("test", args)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Expected error mentioning %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestTokenAndPrint(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("secret-token\n"), 0o600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+
+	cfg, err := Parse("test", []string{"--token-file", tokenFile})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	token, err := cfg.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token != "secret-token" {
+		t.Errorf("Expected token from file, got %q", token)
+	}
+
+	var buf bytes.Buffer
+	if err := cfg.Print(&buf); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "secret-token") {
+		t.Errorf("Printed config must not contain the token: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "token_file: "+tokenFile) {
+		t.Errorf("Expected printed config to contain the token file, got %s", buf.String())
+	}
+}
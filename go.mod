@@ -5,6 +5,7 @@ go 1.23.4
 require (
 	github.com/google/go-github/v74 v74.0.0
 	github.com/metoro-io/mcp-golang v0.16.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -19,5 +20,4 @@ require (
 	github.com/tidwall/pretty v1.2.1 // indirect
 	github.com/tidwall/sjson v1.2.5 // indirect
 	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/metoro-io/mcp-golang/transport"
+)
+
+// maxRequestBodySize bounds the JSON-RPC message a client may POST.
+const maxRequestBodySize = 4 << 20
+
+// jsonRPCInvalidRequest is the JSON-RPC error code of a malformed request.
+const jsonRPCInvalidRequest = -32600
+
+// Timeouts bounding how long a client may take to send a request and how long
+// an idle keep-alive connection is kept. Responses are not bounded, tool calls
+// are limited by the tool timeout instead.
+const (
+	readHeaderTimeout = 10 * time.Second
+	readTimeout       = time.Minute
+	idleTimeout       = 2 * time.Minute
+)
+
+// errServerInitiated is returned for messages the server sends on its own,
+// like notifications, which need a long lived stream this transport does not
+// offer.
+var errServerInitiated = errors.New("http transport cannot deliver server initiated messages")
+
+// httpTransport serves MCP over the streamable HTTP transport: every JSON-RPC
+// message is POSTed to the endpoint and the response is written back in the
+// same HTTP response, so one server process can be shared by many clients.
+type httpTransport struct {
+	addr     string
+	endpoint string
+	server   *http.Server
+
+	mu             sync.Mutex
+	nextID         transport.RequestId
+	pending        map[transport.RequestId]chan *transport.BaseJsonRpcMessage
+	messageHandler func(ctx context.Context, message *transport.BaseJsonRpcMessage)
+	errorHandler   func(error)
+	closeHandler   func()
+}
+
+func newHTTPTransport(addr, endpoint string) *httpTransport {
+	return &httpTransport{
+		addr:     addr,
+		endpoint: endpoint,
+		pending:  make(map[transport.RequestId]chan *transport.BaseJsonRpcMessage),
+	}
+}
+
+// Start listens on the configured address and serves requests in the background.
+func (t *httpTransport) Start(ctx context.Context) error {
+	ln, err := net.Listen("tcp", t.addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(t.endpoint, t)
+	t.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+
+	go func() {
+		err := t.server.Serve(ln)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			t.handleError(err)
+		}
+	}()
+	return nil
+}
+
+// Send delivers a response to the HTTP request waiting for it.
+func (t *httpTransport) Send(ctx context.Context, message *transport.BaseJsonRpcMessage) error {
+	var id transport.RequestId
+	switch message.Type {
+	case transport.BaseMessageTypeJSONRPCResponseType:
+		id = message.JsonRpcResponse.Id
+	case transport.BaseMessageTypeJSONRPCErrorType:
+		id = message.JsonRpcError.Id
+	default:
+		return errServerInitiated
+	}
+
+	t.mu.Lock()
+	ch, ok := t.pending[id]
+	delete(t.pending, id)
+	t.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no pending request with id %d", id)
+	}
+	ch <- message
+	return nil
+}
+
+func (t *httpTransport) Close() error {
+	var err error
+	if t.server != nil {
+		err = t.server.Close()
+	}
+	t.mu.Lock()
+	closeHandler := t.closeHandler
+	t.mu.Unlock()
+	if closeHandler != nil {
+		closeHandler()
+	}
+	return err
+}
+
+func (t *httpTransport) SetCloseHandler(handler func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closeHandler = handler
+}
+
+func (t *httpTransport) SetErrorHandler(handler func(error)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errorHandler = handler
+}
+
+func (t *httpTransport) SetMessageHandler(handler func(ctx context.Context, message *transport.BaseJsonRpcMessage)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messageHandler = handler
+}
+
+func (t *httpTransport) handleError(err error) {
+	t.mu.Lock()
+	errorHandler := t.errorHandler
+	t.mu.Unlock()
+	if errorHandler != nil {
+		errorHandler(err)
+	}
+}
+
+func (t *httpTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	t.mu.Lock()
+	handler := t.messageHandler
+	t.mu.Unlock()
+	if handler == nil {
+		http.Error(w, "server is not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	// only a message without an id is a notification, a request whose id
+	// cannot be handled must not be mistaken for one and silently dropped
+	var probe struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		http.Error(w, "body is not a JSON-RPC request or notification", http.StatusBadRequest)
+		return
+	}
+	if probe.ID != nil {
+		var request transport.BaseJSONRPCRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			writeInvalidRequest(w, probe.ID, err)
+			return
+		}
+		t.handleRequest(w, r, handler, &request)
+		return
+	}
+
+	var notification transport.BaseJSONRPCNotification
+	if err := json.Unmarshal(body, &notification); err == nil {
+		handler(r.Context(), transport.NewBaseMessageNotification(&notification))
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	http.Error(w, "body is not a JSON-RPC request or notification", http.StatusBadRequest)
+}
+
+// writeInvalidRequest answers a request that cannot be decoded, like one with
+// a string id, with a JSON-RPC error echoing the id the client sent.
+func writeInvalidRequest(w http.ResponseWriter, id json.RawMessage, err error) {
+	out, _ := json.Marshal(struct {
+		Jsonrpc string                          `json:"jsonrpc"`
+		ID      json.RawMessage                 `json:"id"`
+		Error   transport.BaseJSONRPCErrorInner `json:"error"`
+	}{
+		Jsonrpc: "2.0",
+		ID:      id,
+		Error: transport.BaseJSONRPCErrorInner{
+			Code:    jsonRPCInvalidRequest,
+			Message: fmt.Sprintf("invalid request: %v", err),
+		},
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(out)
+}
+
+// handleRequest forwards a request under an id unique to this transport, since
+// ids chosen by different clients may collide, and waits for its response.
+func (t *httpTransport) handleRequest(w http.ResponseWriter, r *http.Request,
+	handler func(ctx context.Context, message *transport.BaseJsonRpcMessage), request *transport.BaseJSONRPCRequest) {
+	clientID := request.Id
+	ch := make(chan *transport.BaseJsonRpcMessage, 1)
+
+	t.mu.Lock()
+	t.nextID++
+	request.Id = t.nextID
+	t.pending[request.Id] = ch
+	t.mu.Unlock()
+
+	handler(r.Context(), transport.NewBaseMessageRequest(request))
+
+	var response *transport.BaseJsonRpcMessage
+	select {
+	case response = <-ch:
+	case <-r.Context().Done():
+		t.mu.Lock()
+		delete(t.pending, request.Id)
+		t.mu.Unlock()
+		return
+	}
+
+	switch response.Type {
+	case transport.BaseMessageTypeJSONRPCResponseType:
+		response.JsonRpcResponse.Id = clientID
+	case transport.BaseMessageTypeJSONRPCErrorType:
+		response.JsonRpcError.Id = clientID
+	}
+
+	out, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(out)
+}
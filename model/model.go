@@ -102,6 +102,12 @@ type TagInfo struct {
 type CommitListOption struct {
 	Owner         string `json:"owner" jsonschema:"required,description=owner of the repository"`
 	Repository    string `json:"repository" jsonschema:"required,description=name of the repository"`
+	SHA           string `json:"sha" jsonschema:"description=branch name or commit SHA to start listing commits from, default uses repository's default branch"`
+	Path          string `json:"path" jsonschema:"description=only commits touching this file or directory"`
+	Author        string `json:"author" jsonschema:"description=only commits by this GitHub login or email address"`
+	Committer     string `json:"committer" jsonschema:"description=only commits committed by this GitHub login or email address"`
+	Since         string `json:"since" jsonschema:"description=only commits after this RFC 3339 timestamp or date, e.g. 2024-01-02T15:04:05Z or 2024-01-02"`
+	Until         string `json:"until" jsonschema:"description=only commits before this RFC 3339 timestamp or date, e.g. 2024-01-02T15:04:05Z or 2024-01-02, a date includes the whole day"`
 	ResultPerpage int    `json:"result_per_page" jsonschema:"description=results per page, default to 10"`
 	Page          int    `json:"page" jsonschema:"description=current page number of the search result,start from 1 and default to 1"`
 }
@@ -124,6 +130,73 @@ type CommitInfo struct {
 	ParentCommitHash []string
 }
 
+type GetCommitChecksOption struct {
+	Owner      string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository string `json:"repository" jsonschema:"required,description=name of the repository"`
+	Ref        string `json:"ref" jsonschema:"required,description=commit SHA, branch or tag to report the checks of"`
+}
+
+type CommitChecksResult struct {
+	Ref string
+	// State rolls up all statuses and check runs: failure if any failed,
+	// pending if any has not finished, success otherwise, none without checks.
+	// When LimitReached is set it is pending unless a listed check failed.
+	State   string
+	Success int
+	Failure int
+	Pending int
+	// LimitReached reports that more than 1000 statuses, check runs or check
+	// suites exist and the rest were left out.
+	LimitReached bool
+	Checks       []CheckInfo
+	Suites       []CheckSuiteInfo
+}
+
+type CheckInfo struct {
+	// Kind is check_run for the checks API or status for commit statuses.
+	Kind        string
+	Name        string
+	Status      string
+	Conclusion  string
+	State       string
+	Description string
+	App         string
+	StartedAt   string
+	CompletedAt string
+	DetailsURL  string
+}
+
+type CheckSuiteInfo struct {
+	ID         int64
+	App        string
+	HeadBranch string
+	Status     string
+	Conclusion string
+	CreatedAt  string
+	UpdatedAt  string
+}
+
+type SearchCommitsOption struct {
+	Query         string `json:"query" jsonschema:"required,description=github commit search query, supports qualifiers like repo:, author:, committer-date: and merge:"`
+	Sort          string `json:"sort" jsonschema:"description=sort default by best match, can be [author-date|committer-date]"`
+	Order         string `json:"order" jsonschema:"description=sort order default by desc, can be [desc|asc]"`
+	ResultPerpage int    `json:"result_per_page" jsonschema:"description=results per page, default to 10"`
+	Page          int    `json:"page" jsonschema:"description=current page number of the search result,start from 1 and default to 1"`
+}
+
+type SearchCommitsResult struct {
+	TotalCount int
+	NextPage   int
+	LastPage   int
+	Commits    []CommitSearchInfo
+}
+
+type CommitSearchInfo struct {
+	CommitInfo
+	Owner      string
+	Repository string
+}
+
 type GetCommitBySHAOption struct {
 	Owner      string `json:"owner" jsonschema:"required,description=owner of the repository"`
 	Repository string `json:"repository" jsonschema:"required,description=name of the repository"`
@@ -184,6 +257,25 @@ type DirectoryOrFileInfo struct {
 	Size     int64
 	Type     string
 	Encoding string
+	SHA      string
+}
+
+type GetTreeOption struct {
+	Owner      string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository string `json:"repository" jsonschema:"required,description=name of the repository"`
+	Ref        string `json:"ref" jsonschema:"description=the name of the commit/branch/tag, default uses repository's default branch"`
+	Path       string `json:"path" jsonschema:"description=only list entries under this directory, default to root directory"`
+	Pattern    string `json:"pattern" jsonschema:"description=glob pattern matched against paths relative to path, ** matches any number of directories, a pattern without / matches file names"`
+	MaxDepth   int    `json:"max_depth" jsonschema:"description=maximum directory depth below path, 1 lists direct children only, default to unlimited"`
+	MaxEntries int    `json:"max_entries" jsonschema:"description=maximum number of entries returned, default to 1000"`
+}
+
+type TreeResult struct {
+	SHA               string
+	Truncated         bool
+	EntryLimitReached bool
+	TotalMatches      int
+	Entries           []DirectoryOrFileInfo
 }
 
 type ReadFileOption struct {
@@ -193,6 +285,8 @@ type ReadFileOption struct {
 	Ref        string `json:"ref" jsonschema:"description=the name of the commit/branch/tag, default uses repository's default branch"`
 	StartLine  int    `json:"start_line" jsonschema:"description=starting line number (1-based), default to 1"`
 	EndLine    int    `json:"end_line" jsonschema:"description=ending line number, default to all lines"`
+	StartByte  int    `json:"start_byte" jsonschema:"description=starting byte offset (0-based) of a byte range read, line numbers are ignored when a byte range is given, text ranges are widened to whole UTF-8 characters"`
+	EndByte    int    `json:"end_byte" jsonschema:"description=ending byte offset (exclusive) of a byte range read, default to the end of the file"`
 }
 
 type ReadFileResult struct {
@@ -200,7 +294,77 @@ type ReadFileResult struct {
 	StartLine  int
 	EndLine    int
 	TotalLines int
+	StartByte  int
+	EndByte    int
 	Encoding   string
+	Size       int
+	SHA        string
+	// Binary files are reported by MIMEType and SHA256 without their content,
+	// unless a byte range is read, which returns the range base64 encoded.
+	Binary   bool
+	MIMEType string
+	SHA256   string
+}
+
+type ReadFilesOption struct {
+	Owner         string          `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository    string          `json:"repository" jsonschema:"required,description=name of the repository"`
+	Ref           string          `json:"ref" jsonschema:"description=the name of the commit/branch/tag, default uses repository's default branch"`
+	Files         []FileLineRange `json:"files" jsonschema:"required,description=files to read, at most 50"`
+	MaxOutputSize int             `json:"max_output_size" jsonschema:"description=maximum total size in bytes of the returned content, files beyond it are returned without content, default to 200000"`
+}
+
+type FileLineRange struct {
+	Path      string `json:"path" jsonschema:"required,description=file path to read"`
+	StartLine int    `json:"start_line" jsonschema:"description=starting line number (1-based), default to 1"`
+	EndLine   int    `json:"end_line" jsonschema:"description=ending line number, default to all lines"`
+}
+
+type ReadFilesResult struct {
+	Files []ReadFilesEntry
+	// BudgetExceeded reports that some files were left out of the output
+	// because of MaxOutputSize.
+	BudgetExceeded bool
+}
+
+type ReadFilesEntry struct {
+	Path       string
+	File       *ReadFileResult `json:",omitempty"`
+	Error      string          `json:",omitempty"`
+	OverBudget bool            `json:",omitempty"`
+}
+
+type GetBlameOption struct {
+	Owner      string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository string `json:"repository" jsonschema:"required,description=name of the repository"`
+	Ref        string `json:"ref" jsonschema:"description=the name of the commit/branch, default uses repository's default branch"`
+	Path       string `json:"path" jsonschema:"required,description=file path to blame"`
+	StartLine  int    `json:"start_line" jsonschema:"description=starting line number (1-based), default to 1"`
+	EndLine    int    `json:"end_line" jsonschema:"description=ending line number, default to all lines"`
+	PerLine    bool   `json:"per_line" jsonschema:"description=return one entry per line instead of one per hunk of consecutive lines from the same commit"`
+}
+
+type BlameResult struct {
+	CommitSHA  string
+	Path       string
+	StartLine  int
+	EndLine    int
+	TotalLines int
+	Hunks      []BlameHunk
+}
+
+type BlameHunk struct {
+	StartLine       int
+	EndLine         int
+	CommitSHA       string
+	Author          string
+	AuthorEmail     string
+	AuthorLogin     string
+	Date            string
+	MessageHeadline string
+	HTMLURL         string
+	// Age ranks how recently the lines changed, from 1 (oldest) to 10 (newest).
+	Age int
 }
 
 type FindTagsOption struct {
@@ -260,6 +424,41 @@ type MatchDetail struct {
 	Text    string
 }
 
+type GrepRepositoryOption struct {
+	Owner        string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository   string `json:"repository" jsonschema:"required,description=name of the repository"`
+	Ref          string `json:"ref" jsonschema:"description=the name of the commit/branch/tag, default uses repository's default branch"`
+	Pattern      string `json:"pattern" jsonschema:"required,description=RE2 regular expression matched against each line"`
+	IgnoreCase   bool   `json:"ignore_case" jsonschema:"description=match case insensitively"`
+	Include      string `json:"include" jsonschema:"description=only search paths matching this glob, ** matches any number of directories, a pattern without / matches file names"`
+	Exclude      string `json:"exclude" jsonschema:"description=skip paths matching this glob"`
+	ContextLines int    `json:"context_lines" jsonschema:"description=number of lines before and after a match included in its fragment, default to 0"`
+	MaxMatches   int    `json:"max_matches" jsonschema:"description=maximum number of matching lines returned, default to 100"`
+}
+
+type GrepRepositoryResult struct {
+	CommitSHA     string
+	FilesSearched int
+	TotalMatches  int
+	LimitReached  bool
+	Files         []GrepFileInfo
+}
+
+type GrepFileInfo struct {
+	Path        string
+	TextMatches []GrepMatch
+}
+
+// GrepMatch is a matching line with its context lines in Fragment, match
+// indices are relative to the fragment like in TextMatch.
+type GrepMatch struct {
+	Line      int
+	StartLine int
+	EndLine   int
+	Fragment  string
+	Matches   []MatchDetail
+}
+
 type ListIssuesOption struct {
 	Owner         string   `json:"owner" jsonschema:"required,description=owner of the repository"`
 	Repository    string   `json:"repository" jsonschema:"required,description=name of the repository"`
@@ -386,12 +585,148 @@ type GetPullRequestByNumberOption struct {
 	Number     int    `json:"number" jsonschema:"required,description=the pull request number"`
 }
 
+type ListPullRequestFilesOption struct {
+	Owner        string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository   string `json:"repository" jsonschema:"required,description=name of the repository"`
+	Number       int    `json:"number" jsonschema:"required,description=the pull request number"`
+	Include      string `json:"include" jsonschema:"description=only files whose path matches this glob, ** matches any number of directories, a pattern without / matches file names"`
+	Exclude      string `json:"exclude" jsonschema:"description=skip files whose path matches this glob"`
+	MaxPatchSize int    `json:"max_patch_size" jsonschema:"description=maximum size in bytes of each file patch, longer patches are cut at a line boundary, default to 10000, -1 leaves out patches"`
+}
+
+type PullRequestFilesResult struct {
+	// TotalFiles counts all files of the pull request before filtering.
+	TotalFiles       int
+	Files            []CommitFileInfo
+	TruncatedPatches int
+	// FileLimitReached reports that GitHub stopped listing files at its
+	// limit of 3000 files per pull request.
+	FileLimitReached bool
+}
+
+type GetPullRequestStatusOption struct {
+	Owner      string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository string `json:"repository" jsonschema:"required,description=name of the repository"`
+	Number     int    `json:"number" jsonschema:"required,description=the pull request number"`
+	MaxWait    int    `json:"max_wait" jsonschema:"description=seconds to wait for GitHub to compute mergeability, default to 10, at most 60, -1 does not wait"`
+}
+
+type PullRequestStatusResult struct {
+	Number  int
+	State   string
+	Draft   bool
+	Merged  bool
+	BaseRef string
+	HeadRef string
+	HeadSHA string
+	// Mergeable is mergeable, conflicting or unknown when GitHub did not
+	// finish computing it within the wait.
+	Mergeable      string
+	MergeableState string
+	// Protected reports that the base branch is protected. Reading the
+	// review rules needs admin access, without it ProtectionPartial is set
+	// and only the required checks are known.
+	Protected         bool
+	ProtectionPartial bool
+	// RequiredState and OptionalState roll up the checks like
+	// get_commit_checks, MissingRequired lists required checks that have not
+	// reported on the head commit. ChecksLimitReached reports that checks
+	// beyond the first 1000 were left out, the states are then pending unless
+	// a listed check failed.
+	RequiredState      string
+	OptionalState      string
+	RequiredChecks     []CheckInfo
+	OptionalChecks     []CheckInfo
+	MissingRequired    []string
+	ChecksLimitReached bool
+	// ReviewDecision is APPROVED, CHANGES_REQUESTED, REVIEW_REQUIRED or
+	// empty when no review is required or given.
+	ReviewDecision     string
+	Approvals          int
+	RequiredApprovals  int
+	ChangesRequestedBy []string
+	RequestedReviewers []string
+	AheadBy            int
+	BehindBy           int
+	// Blockers explains what keeps the pull request from being merged.
+	Blockers []string
+}
+
+type ListPullRequestReviewsOption struct {
+	Owner         string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository    string `json:"repository" jsonschema:"required,description=name of the repository"`
+	Number        int    `json:"number" jsonschema:"required,description=the pull request number"`
+	ResultPerpage int    `json:"result_per_page" jsonschema:"description=results per page, default to 10"`
+	Page          int    `json:"page" jsonschema:"description=current page number, start from 1 and default to 1"`
+}
+
+type PullRequestReviewsResult struct {
+	NextPage int
+	LastPage int
+	Reviews  []PullRequestReviewInfo
+}
+
+type PullRequestReviewInfo struct {
+	ID                int64
+	State             string
+	User              string
+	AuthorAssociation string
+	Body              string
+	SubmittedAt       string
+	CommitID          string
+	HTMLURL           string
+}
+
+type ListPullRequestReviewCommentsOption struct {
+	Owner      string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository string `json:"repository" jsonschema:"required,description=name of the repository"`
+	Number     int    `json:"number" jsonschema:"required,description=the pull request number"`
+	ReviewID   int64  `json:"review_id" jsonschema:"description=only threads with a comment from this review"`
+	Path       string `json:"path" jsonschema:"description=only threads on files whose path matches this glob"`
+	Since      string `json:"since" jsonschema:"description=only threads with a comment updated after this time, RFC 3339 timestamp or date like 2024-01-02"`
+}
+
+type PullRequestReviewCommentsResult struct {
+	TotalComments int
+	// Threads holds the comments that start a thread, with the replies
+	// nested below them.
+	Threads []ReviewCommentInfo
+}
+
+type ReviewCommentInfo struct {
+	ID        int64
+	ReviewID  int64
+	InReplyTo int64
+	User      string
+	Body      string
+	Path      string
+	// Line is the line of the comment in the current diff, StartLine is set
+	// for comments on a range of lines.
+	Line      int
+	StartLine int
+	Side      string
+	StartSide string
+	// Outdated comments no longer apply to the diff, OriginalLine is their
+	// line in the diff of OriginalCommitID.
+	Outdated         bool
+	OriginalLine     int
+	SubjectType      string
+	DiffHunk         string
+	CommitID         string
+	OriginalCommitID string
+	CreatedAt        string
+	UpdatedAt        string
+	HTMLURL          string
+	Replies          []ReviewCommentInfo
+}
+
 type SearchPROption struct {
 	Query         string `json:"query" jsonschema:"required,description=github pull request search query"`
 	Sort          string `json:"sort" jsonschema:"description=sort by: created, updated, comments"`
 	Order         string `json:"order" jsonschema:"description=sort order: asc or desc"`
 	ResultPerpage int    `json:"result_per_page" jsonschema:"description=results per page, default to 10"`
 	Page          int    `json:"page" jsonschema:"description=current page number, start from 1 and default to 1"`
+	Hydrate       bool   `json:"hydrate" jsonschema:"description=fetch every pull request found to fill in base and head refs, diff stats and mergeability, costs one request per result"`
 }
 
 type PRListResult struct {
@@ -399,6 +734,9 @@ type PRListResult struct {
 	NextPage   int
 	LastPage   int
 	PRs        []PRInfo
+	// HydrateErrors lists the pull requests of a hydrated search that could
+	// not be fetched, they keep the fields of the search result.
+	HydrateErrors []string `json:"HydrateErrors,omitempty"`
 }
 
 type PRInfo struct {
@@ -450,6 +788,10 @@ type CommitFileInfo struct {
 	RawURL           string `json:"raw_url,omitempty"`
 	ContentsURL      string `json:"contents_url,omitempty"`
 	PreviousFilename string `json:"previous_filename,omitempty"`
+	// PatchTruncated reports that Patch was cut to the requested size,
+	// PatchSize is the size of the full patch.
+	PatchTruncated bool `json:"patch_truncated,omitempty"`
+	PatchSize      int  `json:"patch_size,omitempty"`
 }
 
 type CommitFilesResult struct {
@@ -457,21 +799,331 @@ type CommitFilesResult struct {
 }
 
 type CompareCommitsOption struct {
+	Owner         string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository    string `json:"repository" jsonschema:"required,description=name of the repository"`
+	Base          string `json:"base" jsonschema:"required,description=base commit SHA or branch name"`
+	Head          string `json:"head" jsonschema:"required,description=head commit SHA or branch name"`
+	ResultPerpage int    `json:"result_per_page" jsonschema:"description=commits per page, default to 10"`
+	Page          int    `json:"page" jsonschema:"description=current page number of commits, start from 1 and default to 1"`
+	FilesPerPage  int    `json:"files_per_page" jsonschema:"description=changed files per page, default to 100. GitHub lists at most 300 files, file_limit_reached then means the files may be truncated"`
+	FilePage      int    `json:"file_page" jsonschema:"description=current page number of changed files, start from 1 and default to 1. Files are returned with the first page of commits, later pages only return them when file_page or files_per_page is given"`
+	SummaryOnly   bool   `json:"summary_only" jsonschema:"description=only return the ahead and behind counts and status, without commits and files"`
+}
+
+type GetDiffOption struct {
 	Owner      string `json:"owner" jsonschema:"required,description=owner of the repository"`
 	Repository string `json:"repository" jsonschema:"required,description=name of the repository"`
-	Base       string `json:"base" jsonschema:"required,description=base commit SHA or branch name"`
-	Head       string `json:"head" jsonschema:"required,description=head commit SHA or branch name"`
+	SHA        string `json:"sha" jsonschema:"description=diff of this commit"`
+	Base       string `json:"base" jsonschema:"description=diff from this commit or branch to head"`
+	Head       string `json:"head" jsonschema:"description=diff from base to this commit or branch"`
+	Number     int    `json:"number" jsonschema:"description=diff of this pull request"`
+	Include    string `json:"include" jsonschema:"description=only files whose path matches this glob, ** matches any number of directories, a pattern without / matches file names"`
+	Exclude    string `json:"exclude" jsonschema:"description=skip files whose path matches this glob"`
+	StartFile  int    `json:"start_file" jsonschema:"description=first file to return (1-based, counted after filtering), default to 1"`
+	StartHunk  int    `json:"start_hunk" jsonschema:"description=first hunk to return of the first file (1-based), default to 1"`
+	StartLine  int    `json:"start_line" jsonschema:"description=first line to return of the first hunk (1-based, counting the @@ header), continues a hunk cut at the size budget, default to 1"`
+	MaxSize    int    `json:"max_size" jsonschema:"description=maximum size in bytes of the returned hunks, default to 50000"`
+}
+
+type DiffResult struct {
+	// TotalFiles counts the files left after filtering.
+	TotalFiles int
+	Files      []DiffFile
+	// NextFile, NextHunk and NextLine continue the diff where the size
+	// budget ran out, all are 0 once the end of the diff was returned.
+	// NextLine is only set when a single hunk did not fit the budget.
+	NextFile int
+	NextHunk int
+	NextLine int
+}
+
+type DiffFile struct {
+	Index      int
+	Path       string
+	OldPath    string
+	Status     string
+	Binary     bool
+	Additions  int
+	Deletions  int
+	TotalHunks int
+	Hunks      []DiffHunk
+}
+
+type DiffHunk struct {
+	Index    int
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	// Section is the text after the hunk range, usually the enclosing function.
+	Section string
+	Content string
+	// Truncated hunks alone exceed the size budget and were cut at a line
+	// boundary, the rest is returned from NextLine on. A first line longer
+	// than the budget is returned whole.
+	Truncated bool
 }
 
 type CompareCommitsResult struct {
-	TotalCommits     int           `json:"total_commits"`
-	AheadBy          int           `json:"ahead_by"`
-	BehindBy         int           `json:"behind_by"`
-	Commits          []CommitInfo  `json:"commits"`
-	Files            []CommitFileInfo `json:"files"`
-	HTMLURL          string        `json:"html_url"`
-	PermalinkURL     string        `json:"permalink_url"`
-	DiffURL          string        `json:"diff_url"`
-	PatchURL         string        `json:"patch_url"`
-	Status           string        `json:"status"`
+	TotalCommits int `json:"total_commits"`
+	AheadBy      int `json:"ahead_by"`
+	BehindBy     int `json:"behind_by"`
+	// NextPage and LastPage page through the commits.
+	NextPage int          `json:"next_page"`
+	LastPage int          `json:"last_page"`
+	Commits  []CommitInfo `json:"commits,omitempty"`
+	// TotalFiles counts the changed files GitHub returned, FileNextPage
+	// pages through them. Later pages of commits only return files when
+	// file_page or files_per_page is given.
+	TotalFiles   int              `json:"total_files"`
+	FileNextPage int              `json:"file_next_page"`
+	Files        []CommitFileInfo `json:"files,omitempty"`
+	// CommitLimitReached reports that GitHub left out commits.
+	// FileLimitReached reports that GitHub listed its maximum of 300 files, so
+	// the files may be truncated, get_diff returns every file.
+	CommitLimitReached bool   `json:"commit_limit_reached"`
+	FileLimitReached   bool   `json:"file_limit_reached"`
+	HTMLURL            string `json:"html_url"`
+	PermalinkURL       string `json:"permalink_url"`
+	DiffURL            string `json:"diff_url"`
+	PatchURL           string `json:"patch_url"`
+	Status             string `json:"status"`
+}
+
+type ListWorkflowsOption struct {
+	Owner         string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository    string `json:"repository" jsonschema:"required,description=name of the repository"`
+	ResultPerpage int    `json:"result_per_page" jsonschema:"description=results per page, default to 10"`
+	Page          int    `json:"page" jsonschema:"description=current page number, start from 1 and default to 1"`
+}
+
+type ListWorkflowsResult struct {
+	TotalCount int
+	NextPage   int
+	LastPage   int
+	Workflows  []WorkflowInfo
+}
+
+type WorkflowInfo struct {
+	ID        int64
+	Name      string
+	Path      string
+	State     string
+	HTMLURL   string
+	CreatedAt string
+	UpdatedAt string
+}
+
+type ListWorkflowRunsOption struct {
+	Owner         string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository    string `json:"repository" jsonschema:"required,description=name of the repository"`
+	Workflow      string `json:"workflow" jsonschema:"description=only runs of this workflow, given by ID or file name like ci.yml, default to all workflows"`
+	Branch        string `json:"branch" jsonschema:"description=only runs on this branch"`
+	Event         string `json:"event" jsonschema:"description=only runs triggered by this event, e.g. push, pull_request, schedule"`
+	Status        string `json:"status" jsonschema:"description=only runs with this status or conclusion, e.g. completed, in_progress, queued, success, failure"`
+	Actor         string `json:"actor" jsonschema:"description=only runs triggered by this user login"`
+	HeadSHA       string `json:"head_sha" jsonschema:"description=only runs for this head commit SHA"`
+	ResultPerpage int    `json:"result_per_page" jsonschema:"description=results per page, default to 10"`
+	Page          int    `json:"page" jsonschema:"description=current page number, start from 1 and default to 1"`
+}
+
+type ListWorkflowRunsResult struct {
+	TotalCount int
+	NextPage   int
+	LastPage   int
+	Runs       []WorkflowRunInfo
+}
+
+type WorkflowRunInfo struct {
+	ID           int64
+	WorkflowID   int64
+	Name         string
+	DisplayTitle string
+	RunNumber    int
+	RunAttempt   int
+	Event        string
+	Status       string
+	Conclusion   string
+	HeadBranch   string
+	HeadSHA      string
+	Actor        string
+	HTMLURL      string
+	CreatedAt    string
+	UpdatedAt    string
+	RunStartedAt string
+}
+
+type GetWorkflowRunOption struct {
+	Owner       string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository  string `json:"repository" jsonschema:"required,description=name of the repository"`
+	RunID       int64  `json:"run_id" jsonschema:"required,description=ID of the workflow run"`
+	AllAttempts bool   `json:"all_attempts" jsonschema:"description=include the jobs of all attempts instead of only the latest one"`
+}
+
+type WorkflowRunResult struct {
+	WorkflowRunInfo
+	// JobLimitReached reports that only the first 1000 of TotalJobs jobs
+	// are listed.
+	TotalJobs       int
+	JobLimitReached bool
+	Jobs            []WorkflowJobInfo
+}
+
+type WorkflowJobInfo struct {
+	ID          int64
+	Name        string
+	Status      string
+	Conclusion  string
+	RunAttempt  int64
+	RunnerName  string
+	Labels      []string
+	StartedAt   string
+	CompletedAt string
+	HTMLURL     string
+	Steps       []WorkflowStepInfo
+}
+
+type WorkflowStepInfo struct {
+	Number      int64
+	Name        string
+	Status      string
+	Conclusion  string
+	StartedAt   string
+	CompletedAt string
+}
+
+type GetJobLogsOption struct {
+	Owner        string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository   string `json:"repository" jsonschema:"required,description=name of the repository"`
+	JobID        int64  `json:"job_id" jsonschema:"required,description=ID of the workflow job"`
+	StartLine    int    `json:"start_line" jsonschema:"description=starting line number (1-based), default to 1"`
+	EndLine      int    `json:"end_line" jsonschema:"description=ending line number, default to all lines"`
+	FailuresOnly bool   `json:"failures_only" jsonschema:"description=only return the sections around ##[error] annotations and the last lines of each failed step, start_line and end_line are ignored"`
+	TailLines    *int   `json:"tail_lines" jsonschema:"description=lines returned from the end of each failed step in failures_only mode, 0 returns only the error annotations, default to 30"`
+	ContextLines *int   `json:"context_lines" jsonschema:"description=lines returned before each ##[error] annotation in failures_only mode, 0 returns only the annotation line, default to 5"`
+	Timestamps   bool   `json:"timestamps" jsonschema:"description=keep the timestamp GitHub prefixes each log line with"`
+}
+
+type JobLogsResult struct {
+	JobID      int64
+	JobName    string
+	Status     string
+	Conclusion string
+	TotalLines int
+	StartLine  int
+	EndLine    int
+	Content    string
+	// Sections are set instead of Content in failures_only mode.
+	Sections []LogSection
+}
+
+type LogSection struct {
+	Reason    string
+	Step      string
+	StartLine int
+	EndLine   int
+	Content   string
+}
+
+type ListArtifactsOption struct {
+	Owner         string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository    string `json:"repository" jsonschema:"required,description=name of the repository"`
+	RunID         int64  `json:"run_id" jsonschema:"description=only artifacts of this workflow run, default to all artifacts of the repository"`
+	Name          string `json:"name" jsonschema:"description=only artifacts with this name"`
+	ResultPerpage int    `json:"result_per_page" jsonschema:"description=results per page, default to 10"`
+	Page          int    `json:"page" jsonschema:"description=current page number, start from 1 and default to 1"`
+}
+
+type ListArtifactsResult struct {
+	TotalCount int
+	NextPage   int
+	LastPage   int
+	Artifacts  []ArtifactInfo
+}
+
+type ArtifactInfo struct {
+	ID          int64
+	Name        string
+	SizeInBytes int64
+	Expired     bool
+	RunID       int64
+	HeadBranch  string
+	HeadSHA     string
+	CreatedAt   string
+	ExpiresAt   string
+}
+
+type ReadArtifactOption struct {
+	Owner      string `json:"owner" jsonschema:"required,description=owner of the repository"`
+	Repository string `json:"repository" jsonschema:"required,description=name of the repository"`
+	ArtifactID int64  `json:"artifact_id" jsonschema:"required,description=ID of the artifact"`
+	Path       string `json:"path" jsonschema:"description=path of the entry to read inside the artifact zip, default to listing the entries"`
+	StartLine  int    `json:"start_line" jsonschema:"description=starting line number (1-based), default to 1"`
+	EndLine    int    `json:"end_line" jsonschema:"description=ending line number, default to all lines"`
+}
+
+type ReadArtifactResult struct {
+	Artifact ArtifactInfo
+	// Entries lists the files of the artifact when no path is given.
+	Entries []ArtifactEntry `json:",omitempty"`
+	File    *ReadFileResult `json:",omitempty"`
+}
+
+type ArtifactEntry struct {
+	Path     string
+	Size     uint64
+	Modified string
+}
+
+type RateLimitOption struct {
+}
+
+type RateLimitResult struct {
+	Core       *RateInfo
+	Search     *RateInfo
+	CodeSearch *RateInfo
+	GraphQL    *RateInfo
+}
+
+type RateInfo struct {
+	Limit     int
+	Remaining int
+	Used      int
+	Reset     string
+}
+
+type CacheStatsOption struct {
+}
+
+type CacheStatsResult struct {
+	Enabled    bool
+	Entries    int
+	MaxEntries int
+	TTL        string
+	Dir        string
+	Hits       int64
+	Misses     int64
+	Stores     int64
+	Evictions  int64
+}
+
+type ListToolsetsOption struct {
+}
+
+type ListToolsetsResult struct {
+	ReadOnly bool
+	Toolsets []ToolsetInfo
+}
+
+type ToolsetInfo struct {
+	Name        string
+	Description string
+	Enabled     bool
+	Tools       []ToolInfo
+}
+
+type ToolInfo struct {
+	Name     string
+	ReadOnly bool
+	Enabled  bool
 }
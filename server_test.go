@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Felamande/githubMcp/client"
+	"github.com/Felamande/githubMcp/config"
+	"github.com/Felamande/githubMcp/model"
+	mcpgo "github.com/metoro-io/mcp-golang"
+	"github.com/metoro-io/mcp-golang/transport"
+)
+
+func newTestHTTPServer(t *testing.T, cfg *config.Config, opts ...client.Option) *httptest.Server {
+	t.Helper()
+	tr := newHTTPTransport("127.0.0.1:0", "/mcp")
+	server := mcpgo.NewServer(tr)
+	c, err := client.NewClient("", opts...)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if err := registerTools(server, c, cfg); err != nil {
+		t.Fatalf("registerTools failed: %v", err)
+	}
+	if err := server.Serve(); err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	t.Cleanup(func() { tr.Close() })
+
+	ts := httptest.NewServer(tr)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func postJSONRPC(t *testing.T, url, body string) (*http.Response, map[string]interface{}) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var out map[string]interface{}
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return resp, out
+}
+
+func TestHTTPTransportListTools(t *testing.T) {
+	ts := newTestHTTPServer(t, config.Default())
+
+	resp, out := postJSONRPC(t, ts.URL, `{"jsonrpc":"2.0","id":7,"method":"tools/list","params":{}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if id, _ := out["id"].(float64); id != 7 {
+		t.Errorf("Expected response id 7, got %v", out["id"])
+	}
+
+	result, _ := out["result"].(map[string]interface{})
+	tools, _ := result["tools"].([]interface{})
+	names := make(map[string]bool)
+	for _, tool := range tools {
+		if m, ok := tool.(map[string]interface{}); ok {
+			names[m["name"].(string)] = true
+		}
+	}
+	for _, name := range []string{"read_file", "list_issues", "compare_commits"} {
+		if !names[name] {
+			t.Errorf("Expected tool %s to be registered over http, got %v", name, names)
+		}
+	}
+}
+
+func TestHTTPTransportErrorAndNotification(t *testing.T) {
+	ts := newTestHTTPServer(t, config.Default())
+
+	resp, out := postJSONRPC(t, ts.URL, `{"jsonrpc":"2.0","id":3,"method":"no/such/method","params":{}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if id, _ := out["id"].(float64); id != 3 {
+		t.Errorf("Expected error response id 3, got %v", out["id"])
+	}
+	if _, ok := out["error"]; !ok {
+		t.Errorf("Expected an error response, got %v", out)
+	}
+
+	resp, _ = postJSONRPC(t, ts.URL, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected status 202 for a notification, got %d", resp.StatusCode)
+	}
+
+	getResp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	getResp.Body.Close()
+	if getResp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 for GET, got %d", getResp.StatusCode)
+	}
+}
+
+func TestHTTPTransportInvalidRequests(t *testing.T) {
+	ts := newTestHTTPServer(t, config.Default())
+
+	resp, out := postJSONRPC(t, ts.URL, `{"jsonrpc":"2.0","id":"abc","method":"tools/list","params":{}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if out["id"] != "abc" {
+		t.Errorf("Expected the string id to be echoed, got %v", out["id"])
+	}
+	errObj, _ := out["error"].(map[string]interface{})
+	if code, _ := errObj["code"].(float64); code != -32600 {
+		t.Errorf("Expected error code -32600 for a string id, got %v", out)
+	}
+
+	large := `{"jsonrpc":"2.0","method":"notifications/initialized","params":{"pad":"` + strings.Repeat("x", maxRequestBodySize) + `"}}`
+	resp, _ = postJSONRPC(t, ts.URL, large)
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413 for an oversized body, got %d", resp.StatusCode)
+	}
+}
+
+func TestHTTPTransportRejectsServerInitiated(t *testing.T) {
+	tr := newHTTPTransport("127.0.0.1:0", "/mcp")
+	notification := transport.NewBaseMessageNotification(&transport.BaseJSONRPCNotification{Jsonrpc: "2.0", Method: "notifications/tools/list_changed"})
+	if err := tr.Send(context.Background(), notification); err == nil {
+		t.Errorf("Expected an error for a server initiated notification")
+	}
+}
+
+func TestToolTimeoutCancelsGithubRequest(t *testing.T) {
+	cancelled := make(chan struct{})
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(cancelled)
+		case <-time.After(5 * time.Second):
+			http.Error(w, "too slow", http.StatusGatewayTimeout)
+		}
+	}))
+	defer github.Close()
+
+	cfg := config.Default()
+	cfg.ToolTimeouts = map[string]time.Duration{"get_readme": 50 * time.Millisecond}
+	ts := newTestHTTPServer(t, cfg, client.WithEnterpriseURLs(github.URL, ""))
+
+	start := time.Now()
+	resp, out := postJSONRPC(t, ts.URL, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_readme","arguments":{"owner":"octo","repository":"hello"}}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the tool call to stop at its timeout, took %s", elapsed)
+	}
+
+	result, _ := out["result"].(map[string]interface{})
+	if isError, _ := result["isError"].(bool); !isError {
+		t.Errorf("Expected an error result, got %v", out)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the GitHub request to be cancelled")
+	}
+}
+
+func TestRegisterToolsRejectsUnknownTimeout(t *testing.T) {
+	c, err := client.NewClient("")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	cfg := config.Default()
+	cfg.ToolTimeouts = map[string]time.Duration{"no_such_tool": time.Second}
+	if err := registerTools(mcpgo.NewServer(newHTTPTransport("", "/mcp")), c, cfg); err == nil {
+		t.Errorf("Expected an error for a timeout of an unknown tool")
+	}
+}
+
+func listToolNames(t *testing.T, url string) map[string]bool {
+	t.Helper()
+	_, out := postJSONRPC(t, url, `{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{}}`)
+	result, _ := out["result"].(map[string]interface{})
+	tools, _ := result["tools"].([]interface{})
+	names := make(map[string]bool)
+	for _, tool := range tools {
+		if m, ok := tool.(map[string]interface{}); ok {
+			names[m["name"].(string)] = true
+		}
+	}
+	return names
+}
+
+func TestToolsetGating(t *testing.T) {
+	cfg := config.Default()
+	cfg.Toolsets = []string{"issues"}
+	ts := newTestHTTPServer(t, cfg)
+
+	names := listToolNames(t, ts.URL)
+	if !names["list_issues"] || !names["list_toolsets"] {
+		t.Errorf("Expected issue tools and list_toolsets to be registered, got %v", names)
+	}
+	if names["read_file"] || names["list_pull_requests"] {
+		t.Errorf("Expected tools of disabled toolsets to be hidden, got %v", names)
+	}
+
+	_, out := postJSONRPC(t, ts.URL, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list_toolsets","arguments":{}}}`)
+	result, _ := out["result"].(map[string]interface{})
+	content, _ := result["content"].([]interface{})
+	if len(content) != 1 {
+		t.Fatalf("Expected one content item, got %v", out)
+	}
+	text, _ := content[0].(map[string]interface{})["text"].(string)
+	var toolsets struct {
+		Toolsets []struct {
+			Name    string
+			Enabled bool
+		}
+	}
+	if err := json.Unmarshal([]byte(text), &toolsets); err != nil {
+		t.Fatalf("failed to decode list_toolsets result: %v", err)
+	}
+	for _, toolset := range toolsets.Toolsets {
+		if toolset.Enabled != (toolset.Name == "issues") {
+			t.Errorf("Unexpected enabled state of toolset %s: %v", toolset.Name, toolset.Enabled)
+		}
+	}
+
+	if _, err := newToolRegistry(githubToolsets(nil), []string{"wiki"}, false); err == nil {
+		t.Errorf("Expected an error for an unknown toolset")
+	}
+}
+
+func TestReadOnlyHidesWriteTools(t *testing.T) {
+	toolsets := []toolset{{name: "issues", tools: []tool{
+		readTool("list_issues", "list issues", func(ctx context.Context, opt model.ListToolsetsOption) (string, error) {
+			return "", nil
+		}),
+		writeTool("create_issue", "create an issue", func(ctx context.Context, opt model.ListToolsetsOption) (string, error) {
+			return "", nil
+		}),
+	}}}
+
+	for _, readOnly := range []bool{true, false} {
+		registry, err := newToolRegistry(toolsets, []string{"all"}, readOnly)
+		if err != nil {
+			t.Fatalf("newToolRegistry failed: %v", err)
+		}
+		result, err := registry.listToolsets(context.Background(), model.ListToolsetsOption{})
+		if err != nil {
+			t.Fatalf("listToolsets failed: %v", err)
+		}
+		for _, tool := range result.Toolsets[0].Tools {
+			if tool.Enabled != (tool.ReadOnly || !readOnly) {
+				t.Errorf("Unexpected enabled state in read-only mode %v: %+v", readOnly, tool)
+			}
+		}
+
+		tr := newHTTPTransport("127.0.0.1:0", "/mcp")
+		server := mcpgo.NewServer(tr)
+		if err := registry.register(server, time.Minute, nil); err != nil {
+			t.Fatalf("register failed: %v", err)
+		}
+		if err := server.Serve(); err != nil {
+			t.Fatalf("Serve failed: %v", err)
+		}
+		ts := httptest.NewServer(tr)
+		registered := listToolNames(t, ts.URL)
+		ts.Close()
+		tr.Close()
+		if !registered["list_issues"] || registered["create_issue"] != !readOnly {
+			t.Errorf("Unexpected tools registered in read-only mode %v: %v", readOnly, registered)
+		}
+	}
+}
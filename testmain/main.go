@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/Felamande/githubMcp/client"
@@ -9,8 +10,11 @@ import (
 )
 
 func main() {
-	client := client.NewClient("")
-	r, err := client.ListReleases(model.ReleaseListOption{
+	client, err := client.NewClient("")
+	if err != nil {
+		panic(err)
+	}
+	r, err := client.ListReleases(context.Background(), model.ReleaseListOption{
 		Owner:      "koreader",
 		Repository: "koreader",
 	})
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Felamande/githubMcp/client"
+	"github.com/Felamande/githubMcp/model"
+	mcpgo "github.com/metoro-io/mcp-golang"
+)
+
+// tool is a tool that can be registered on the MCP server.
+type tool struct {
+	name        string
+	description string
+	readOnly    bool
+	// register registers the tool, bounding every call with timeout
+	register func(server *mcpgo.Server, timeout time.Duration) error
+}
+
+// toolset is a group of tools that is enabled or disabled as a whole.
+type toolset struct {
+	name        string
+	description string
+	tools       []tool
+}
+
+// githubToolsets lists all toolsets served by the server.
+func githubToolsets(c *client.GithubClient) []toolset {
+	return []toolset{
+		{
+			name:        "repos",
+			description: "repositories, files, branches, tags, releases, commits and code search",
+			tools: []tool{
+				readTool("search_github_repository", "search github repositories using github search syntax", c.GetRepository),
+				readTool("get_releases", "get releases of the repository", c.ListReleases),
+				readTool("get_readme", "get readme of the repository from start line to end line", c.GetReadme),
+				readTool("get_tags", "list tags of the repository", c.ListTags),
+				readTool("get_tag", "get detailed information about a specific tag by name", c.GetTagByName),
+				readTool("list_commits", "list commits of the repository, optionally filtered by branch, path, author, committer and time window", c.ListCommits),
+				readTool("search_commits", "search commits across GitHub using GitHub search syntax", c.SearchCommits),
+				readTool("get_commit", "get commit details by SHA hash", c.GetCommitBySHA),
+				readTool("get_commit_files", "get file changes for a specific commit by SHA hash", c.GetCommitFilesBySHA),
+				readTool("get_commit_checks", "get the commit statuses, check runs and check suites of a commit, branch or tag with an overall state", c.GetCommitChecks),
+				readTool("compare_commits", "compare two commits or branches with paged commits and files, or only the ahead/behind summary", c.CompareCommits),
+				readTool("get_diff", "get the full unified diff of a commit, compare range or pull request parsed into files and hunks, paged by file and hunk within a size budget", c.GetDiff),
+				readTool("list_branches", "list branches of the repository", c.ListBranches),
+				readTool("get_branch", "get detailed information about a specific branch by name", c.GetBranchByName),
+				readTool("list_directory", "list directories and files in a repository directory", c.ListDirectory),
+				readTool("get_tree", "recursively list the files and directories of a repository with path prefix, glob and depth filters", c.GetTree),
+				readTool("read_file", "read file content with line or byte range support, binary files are described by size, MIME type and sha256", c.ReadFile),
+				readTool("read_files", "read several files of one ref at once with optional line ranges and a total output size budget", c.ReadFiles),
+				readTool("get_blame", "get the commit, author, date and message headline that last changed each line or hunk of a file", c.GetBlame),
+				readTool("find_tags", "find tags matching a regex pattern", c.FindTags),
+				readTool("find_branches", "find branches matching a regex pattern", c.FindBranches),
+				readTool("grep_repository", "search the files of a repository ref with a regular expression, path globs and context lines, works on any branch unlike search_code", c.GrepRepository),
+				readTool("search_code", "search code across GitHub repositories", c.SearchCode),
+			},
+		},
+		{
+			name:        "issues",
+			description: "issues, issue comments and labels",
+			tools: []tool{
+				readTool("list_issues", "list repository issues with filtering", c.ListIssues),
+				readTool("search_issues", "search issues across GitHub", c.SearchIssues),
+				readTool("get_issue", "get detailed information about a specific issue by number", c.GetIssueByNumber),
+				readTool("list_issue_comments", "list comments for a specific issue", c.ListIssueComments),
+				readTool("list_issue_labels", "list all labels available in a repository", c.ListIssueLabels),
+			},
+		},
+		{
+			name:        "pulls",
+			description: "pull requests, their files and reviews",
+			tools: []tool{
+				readTool("list_pull_requests", "list repository pull requests with filtering", c.ListPullRequests),
+				readTool("get_pull_request_by_number", "get detailed information about a specific pull request by number", c.GetPullRequestByNumber),
+				readTool("get_pull_request_status", "summarize whether a pull request can be merged: mergeability, required and optional checks, review decision, branch protection blockers and how far it is behind its base", c.GetPullRequestStatus),
+				readTool("list_pull_request_files", "list the changed files of a pull request with their patches, filtered by path globs and with a per-file patch size cap", c.ListPullRequestFiles),
+				readTool("list_pull_request_reviews", "list the reviews of a pull request with their state, author, body and reviewed commit", c.ListPullRequestReviews),
+				readTool("list_pull_request_review_comments", "list the review comments of a pull request as threads with file path, line, side, diff hunk and nested replies", c.ListPullRequestReviewComments),
+				readTool("search_pull_requests", "search pull requests across GitHub, optionally hydrated with refs, diff stats and mergeability", c.SearchPullRequests),
+			},
+		},
+		{
+			name:        "actions",
+			description: "GitHub Actions workflows, runs, jobs, logs and artifacts",
+			tools: []tool{
+				readTool("list_workflows", "list the GitHub Actions workflows of a repository", c.ListWorkflows),
+				readTool("list_workflow_runs", "list workflow runs of a repository or workflow, filtered by branch, event, status, actor and head SHA", c.ListWorkflowRuns),
+				readTool("get_workflow_run", "get a workflow run with its jobs and their steps", c.GetWorkflowRun),
+				readTool("list_artifacts", "list the Actions artifacts of a repository or workflow run", c.ListArtifacts),
+				readTool("read_artifact", "list the entries of an Actions artifact or read a text entry with a line range", c.ReadArtifact),
+				readTool("get_job_logs", "get the log of a workflow job by line range, or only the error annotations and the end of each failed step", c.GetJobLogs),
+			},
+		},
+		{
+			name:        "meta",
+			description: "API quota and response cache of the server",
+			tools: []tool{
+				readTool("get_rate_limit", "get the remaining core, search and graphql API quotas and when they reset", c.GetRateLimit),
+				readTool("get_cache_stats", "get the hit and miss counts of the response cache", c.GetCacheStats),
+			},
+		},
+	}
+}
+
+// readTool creates a tool that only reads from GitHub.
+func readTool[T any, R any](name, description string, fn func(context.Context, T) (R, error)) tool {
+	return newTool(name, description, true, fn)
+}
+
+// writeTool creates a tool that modifies GitHub, it is hidden in read-only mode.
+func writeTool[T any, R any](name, description string, fn func(context.Context, T) (R, error)) tool {
+	return newTool(name, description, false, fn)
+}
+
+func newTool[T any, R any](name, description string, readOnly bool, fn func(context.Context, T) (R, error)) tool {
+	return tool{
+		name:        name,
+		description: description,
+		readOnly:    readOnly,
+		register: func(server *mcpgo.Server, timeout time.Duration) error {
+			return server.RegisterTool(name, description, toolHandler(fn, timeout))
+		},
+	}
+}
+
+// toolHandler wraps fn into a handler returning its result as JSON text. The
+// request context is cancelled when the caller gives up or timeout expires.
+func toolHandler[T any, R any](fn func(context.Context, T) (R, error), timeout time.Duration) func(context.Context, T) (*mcpgo.ToolResponse, error) {
+	return func(ctx context.Context, opt T) (*mcpgo.ToolResponse, error) {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		result, err := fn(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		out, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		return mcpgo.NewToolResponse(mcpgo.NewTextContent(string(out))), nil
+	}
+}
+
+// toolRegistry decides which tools are exposed: only tools of the enabled
+// toolsets, and only read-only tools in read-only mode.
+type toolRegistry struct {
+	toolsets []toolset
+	enabled  map[string]bool
+	readOnly bool
+}
+
+// newToolRegistry enables the named toolsets, "all" enables every toolset.
+func newToolRegistry(toolsets []toolset, enabledToolsets []string, readOnly bool) (*toolRegistry, error) {
+	r := &toolRegistry{
+		toolsets: toolsets,
+		enabled:  make(map[string]bool),
+		readOnly: readOnly,
+	}
+	for _, name := range enabledToolsets {
+		if name == "all" {
+			for _, ts := range toolsets {
+				r.enabled[ts.name] = true
+			}
+			continue
+		}
+		if r.toolset(name) == nil {
+			return nil, fmt.Errorf("unknown toolset: %s", name)
+		}
+		r.enabled[name] = true
+	}
+	return r, nil
+}
+
+func (r *toolRegistry) toolset(name string) *toolset {
+	for i := range r.toolsets {
+		if r.toolsets[i].name == name {
+			return &r.toolsets[i]
+		}
+	}
+	return nil
+}
+
+func (r *toolRegistry) isKnownTool(name string) bool {
+	for _, ts := range r.toolsets {
+		for _, t := range ts.tools {
+			if t.name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *toolRegistry) toolEnabled(ts toolset, t tool) bool {
+	return r.enabled[ts.name] && (t.readOnly || !r.readOnly)
+}
+
+// register registers the enabled tools and list_toolsets on the server.
+func (r *toolRegistry) register(server *mcpgo.Server, defaultTimeout time.Duration, timeouts map[string]time.Duration) error {
+	for name := range timeouts {
+		if !r.isKnownTool(name) {
+			return fmt.Errorf("unknown tool in tool timeouts: %s", name)
+		}
+	}
+
+	for _, ts := range r.toolsets {
+		for _, t := range ts.tools {
+			if !r.toolEnabled(ts, t) {
+				continue
+			}
+			timeout, ok := timeouts[t.name]
+			if !ok {
+				timeout = defaultTimeout
+			}
+			if err := t.register(server, timeout); err != nil {
+				return fmt.Errorf("failed to register tool %s: %v", t.name, err)
+			}
+		}
+	}
+
+	return server.RegisterTool("list_toolsets", "list the toolsets of the server and which of their tools are enabled",
+		toolHandler(r.listToolsets, defaultTimeout))
+}
+
+func (r *toolRegistry) listToolsets(ctx context.Context, opt model.ListToolsetsOption) (*model.ListToolsetsResult, error) {
+	result := &model.ListToolsetsResult{
+		ReadOnly: r.readOnly,
+		Toolsets: make([]model.ToolsetInfo, 0, len(r.toolsets)),
+	}
+	for _, ts := range r.toolsets {
+		toolsetInfo := model.ToolsetInfo{
+			Name:        ts.name,
+			Description: ts.description,
+			Enabled:     r.enabled[ts.name],
+			Tools:       make([]model.ToolInfo, 0, len(ts.tools)),
+		}
+		for _, t := range ts.tools {
+			toolsetInfo.Tools = append(toolsetInfo.Tools, model.ToolInfo{
+				Name:     t.name,
+				ReadOnly: t.readOnly,
+				Enabled:  r.toolEnabled(ts, t),
+			})
+		}
+		result.Toolsets = append(result.Toolsets, toolsetInfo)
+	}
+	return result, nil
+}